@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/JayceChant/commit-msg/state"
+	"github.com/JayceChant/commit-msg/validator"
+)
+
+// runCheck validates the messages in the files of paths, "-" for stdin,
+// or the NUL-separated messages from stdin in batch mode, and reports in format.
+// A single file in text format is reported the same way as the hook does.
+func runCheck(paths []string, batch bool, format string) {
+	checkFormat(format)
+	if !batch && len(paths) <= 1 && format == "text" {
+		validator.Validate(firstPath(paths))
+		return
+	}
+
+	cfg, err := validator.LoadCurrentConfig()
+	if err != nil {
+		state.ConfigError.LogAndExit(err)
+	}
+
+	check := func(r *report, path, msg string) {
+		res, err := validator.Check(msg, *cfg)
+		if err != nil {
+			state.ConfigError.LogAndExit(err)
+		}
+		r.add(path, path, res)
+	}
+
+	r := report{format: format}
+	if batch {
+		if len(paths) > 0 {
+			log.Println("no file is accepted with -z, messages are read from stdin")
+			os.Exit(2)
+		}
+
+		buf, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			log.Println(err)
+			state.ReadError.LogAndExit(validator.Stdin)
+		}
+		for i, msg := range splitBatch(string(buf)) {
+			check(&r, fmt.Sprintf("%s#%d", validator.Stdin, i+1), msg)
+		}
+	} else {
+		for _, p := range paths {
+			msg, s, err := validator.ReadMessage(p)
+			if err != nil {
+				r.addError(p, s, err)
+				continue
+			}
+			check(&r, p, msg)
+		}
+	}
+	r.exit("messages")
+}
+
+// splitBatch splits the NUL-separated messages, the NUL after the last one is optional
+func splitBatch(buf string) []string {
+	if buf == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(buf, "\x00"), "\x00")
+}
+
+// firstPath returns the first path, or "" if none
+func firstPath(paths []string) string {
+	if len(paths) == 0 {
+		return ""
+	}
+	return paths[0]
+}
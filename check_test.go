@@ -0,0 +1,27 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitBatch(t *testing.T) {
+	var batchCases = []struct {
+		name string
+		buf  string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"single", "feat: add x", []string{"feat: add x"}},
+		{"trailing_nul", "feat: add x\x00", []string{"feat: add x"}},
+		{"multiple", "feat: add x\x00fix: y\x00", []string{"feat: add x", "fix: y"}},
+		{"multi_line", "feat: add x\n\nbody\nmore body\n\x00fix: y\n\nRefs: #12\n", []string{"feat: add x\n\nbody\nmore body\n", "fix: y\n\nRefs: #12\n"}},
+		{"empty_message", "feat: add x\x00\x00fix: y", []string{"feat: add x", "", "fix: y"}},
+		{"only_nul", "\x00", []string{""}},
+	}
+	for _, tt := range batchCases {
+		if got := splitBatch(tt.buf); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: splitBatch got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
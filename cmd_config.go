@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/JayceChant/commit-msg/state"
+	"github.com/JayceChant/commit-msg/validator"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultSource = "default"
+	typesSource   = "types, denyTypes"
+)
+
+// annotated is a config value with the config files it comes from
+type annotated struct {
+	Value  interface{} `json:"value"`
+	Source string      `json:"source"`
+}
+
+// runConfig prints the effective config merged from all the config files
+func runConfig(args []string) {
+	fs := flag.NewFlagSet("config", flag.ExitOnError)
+	format := fs.String("format", "json", "output format, json or yaml")
+	sources := fs.Bool("sources", false, "annotate each value with the config files it comes from, and list the allowed types")
+	fs.Parse(args)
+
+	cfg, err := validator.LoadCurrentConfig()
+	if err != nil {
+		state.ConfigError.LogAndExit(err)
+	}
+
+	var out []byte
+	switch strings.ToLower(*format) {
+	case "json":
+		out, err = configJSON(cfg, *sources)
+	case "yaml", "yml":
+		out, err = configYAML(cfg, *sources)
+	default:
+		log.Printf("unknown format %q, should be json or yaml\n", *format)
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Println(err)
+		os.Exit(int(state.UndefindedError))
+	}
+	os.Stdout.Write(out)
+}
+
+func sourceOf(item validator.Item) string {
+	if item.Source == "" {
+		return defaultSource
+	}
+	return item.Source
+}
+
+// items returns the config items, with nil lists as empty ones to print
+func items(cfg *validator.Config) []validator.Item {
+	items := cfg.Items()
+	for i, item := range items {
+		if list, ok := item.Value.([]string); ok && list == nil {
+			items[i].Value = []string{}
+		}
+		if v := reflect.ValueOf(item.Value); v.Kind() == reflect.Map && v.IsNil() {
+			items[i].Value = reflect.MakeMap(v.Type()).Interface()
+		}
+	}
+	return items
+}
+
+// configJSON encodes the config as JSON, keeping the order of items
+func configJSON(cfg *validator.Config, sources bool) ([]byte, error) {
+	all := items(cfg)
+	if sources {
+		for i, item := range all {
+			all[i].Value = annotated{Value: item.Value, Source: sourceOf(item)}
+		}
+		all = append(all, validator.Item{Key: "allowedTypes", Value: annotated{Value: cfg.AllowedTypes(), Source: typesSource}})
+	}
+	return itemsJSON(all)
+}
+
+// itemsJSON encodes the items as a JSON object, keeping their order
+func itemsJSON(items []validator.Item) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, item := range items {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(item.Key)
+		v, err := json.Marshal(item.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", "    "); err != nil {
+		return nil, err
+	}
+	out.WriteByte('\n')
+	return out.Bytes(), nil
+}
+
+// configYAML encodes the config as YAML, the sources are written as line comments
+func configYAML(cfg *validator.Config, sources bool) ([]byte, error) {
+	doc := &yaml.Node{Kind: yaml.MappingNode}
+	add := func(key string, value interface{}, comment string) error {
+		v := &yaml.Node{}
+		if err := v.Encode(value); err != nil {
+			return err
+		}
+		if v.Kind == yaml.SequenceNode {
+			v.Style = yaml.FlowStyle
+		}
+		k := &yaml.Node{Kind: yaml.ScalarNode, Value: key}
+		if v.Kind == yaml.MappingNode && len(v.Content) > 0 {
+			// a line comment of block mapping is lost, put it after the key
+			k.LineComment = comment
+		} else {
+			v.LineComment = comment
+		}
+		doc.Content = append(doc.Content, k, v)
+		return nil
+	}
+
+	for _, item := range items(cfg) {
+		comment := ""
+		if sources {
+			comment = "from " + sourceOf(item)
+		}
+		if err := add(item.Key, item.Value, comment); err != nil {
+			return nil, err
+		}
+	}
+
+	if sources {
+		if err := add("allowedTypes", cfg.AllowedTypes(), "from "+typesSource); err != nil {
+			return nil, err
+		}
+	}
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/JayceChant/commit-msg/dir"
+	"github.com/JayceChant/commit-msg/state"
+	"github.com/JayceChant/commit-msg/validator"
+)
+
+var (
+	// presets are the named configs to start with, on top of the defaults
+	presets = map[string]*validator.Config{
+		"default": {},
+		// https://github.com/conventional-changelog/commitlint/tree/master/@commitlint/config-conventional
+		"conventional": {
+			LineLimit:      100,
+			DenyTypes:      []string{"docker"},
+			DenySubjectEnd: []string{"."},
+		},
+		// https://github.com/angular/angular/blob/main/CONTRIBUTING.md#type
+		"angular": {
+			LineLimit:      100,
+			DenyTypes:      []string{"chore", "docker", "style"},
+			DenySubjectEnd: []string{"."},
+		},
+		"strict": {
+			BodyRequired:            true,
+			ScopeRequired:           true,
+			LineLimit:               72,
+			DenySubjectLeadingSpace: true,
+			SubjectCase:             "lower",
+			DenySubjectEnd:          []string{".", "。"},
+			SubjectMaxLength:        50,
+			SubjectImperative:       true,
+		},
+	}
+
+	// itemComments describe the config items in the generated config file
+	itemComments = map[string]string{
+		"lang":                    "prompt language, en or zh, or xx with translation file commit-msg.xx.json",
+		"bodyRequired":            "if true, message body must be contained, not only message header",
+		"lineLimit":               "length limit of every single line, in bytes, 0 to skip line length checking",
+		"types":                   "keywords added to the default type keywords, list \"...\" to keep those inherited",
+		"denyTypes":               "keywords removed from the type keywords",
+		"scopeRequired":           "if true, (<scope>) will be required right after type",
+		"scopes":                  "if not empty, scope must match one of the list, a keyword, a glob pattern e.g. pkg/*, or a regular expression in slashes e.g. \"/svc-\\\\w+/\"",
+		"multipleScopes":          "if true, several scopes separated by scopeDelimiter are allowed, e.g. feat(api,ui), each must match scopes",
+		"scopePaths":              "scopes of the changed files by path patterns, e.g. services/billing/**: billing, the scope must cover those of the staged files",
+		"scopeDelimiter":          "delimiter between the scopes if multipleScopes is true, \",\" if empty, e.g. \"/\"",
+		"denySubjectLeadingSpace": "if true, the subject must not start with whitespace, only one space is allowed after the colon",
+		"subjectCase":             "case required of the subject, lower or upper for all the letters, sentence for the first letter upper, or any",
+		"denySubjectEnd":          "punctuations the subject must not end with, e.g. [\".\", \"。\"]",
+		"subjectMinLength":        "minimum length of the subject in characters, 0 for no limit",
+		"subjectMaxLength":        "maximum length of the subject in characters, independent of lineLimit, 0 for no limit",
+		"subjectImperative":       "if true, the subject must start with a verb in imperative mood, e.g. add rather than added, adds or adding",
+		"breakingFooter":          "required: a header marked by \"!\" requires a BREAKING CHANGE footer and vice versa, forbidden: mark by \"!\" only, or any",
+		"trailers":                "trailer tokens allowed in the footer, e.g. Refs, Signed-off-by, any token is allowed if empty",
+		"requiredTrailers":        "trailer tokens required in the footer",
+		"trailerPatterns":         "regular expressions the values of the trailers must match, e.g. Refs: \"^#\\\\d+$\"",
+		"issuePattern":            "regular expression of the issue reference required, e.g. \"[A-Z]+-\\\\d+\" or \"#\\\\d+\", empty for none",
+		"issueLocations":          "where the issue reference may appear, subject, body or trailers, all if empty",
+		"issueTrailers":           "trailer tokens where the issue reference may appear, e.g. Refs, any if empty",
+		"issuePrefixes":           "project prefixes allowed of the issue reference, e.g. PROJ, any if empty",
+		"branchTicketPattern":     "regular expression of the ticket key in the branch name, e.g. \"[A-Z]+-\\\\d+\", which must be referenced in the message if found",
+		"typeRules":               "rules of scope and body for the types, \"*\" for the types not listed, e.g. docs: {scope: forbidden, body: optional}, ci: {scopes: [github]}",
+		"branches":                "config overriding this one on the branches matching the patterns, e.g. release/*: {bodyRequired: true}",
+		"severity":                "severity of the rules named by their states, error (default), warning or off, .header or .body to limit the rule, e.g. LineOverLong.body: warning",
+	}
+
+	// skipDirs are the top level directories not taken as scopes
+	skipDirs = map[string]bool{
+		"node_modules": true,
+		"testdata":     true,
+		"vendor":       true,
+	}
+)
+
+// runInit writes a config file with all the items commented, those not set
+// by the preset, detected or prompted are commented out to inherit the upper configs,
+// at the repository root or in the hooks dir
+func runInit(args []string) {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	format := fs.String("format", "yaml", "format of the config file, json, yaml or toml, json allows no comments")
+	preset := fs.String("preset", "default", "preset to start with, one of "+strings.Join(presetNames(), ", "))
+	hooks := fs.Bool("hooks", false, "write the local config into the hooks dir, instead of the project config at the repository root")
+	interactive := fs.Bool("i", false, "prompt for the values of the config items")
+	force := fs.Bool("force", false, "overwrite the existing config file")
+	fs.Parse(args)
+
+	*format = strings.ToLower(*format)
+	if *format != "json" && *format != "yaml" && *format != "toml" {
+		log.Printf("unknown format %q, should be json, yaml or toml\n", *format)
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	p, ok := presets[*preset]
+	if !ok {
+		log.Printf("unknown preset %q, should be one of %s\n", *preset, strings.Join(presetNames(), ", "))
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	cfg, set := initConfig(p, dir.RootDir())
+	if *interactive {
+		prompt(cfg, set, bufio.NewScanner(os.Stdin))
+	}
+
+	if err := cfg.Validate(); err != nil {
+		state.ConfigError.LogAndExit(err)
+	}
+
+	target := dir.RootDir()
+	if *hooks {
+		target = dir.HooksDir()
+	}
+	path := filepath.Join(target, validator.ConfigName+"."+*format)
+
+	if _, err := os.Stat(path); err == nil && !*force {
+		log.Printf("%s already exists, use -force to overwrite it\n", path)
+		os.Exit(1)
+	}
+
+	for _, ext := range []string{"json", "yaml", "yml", "toml"} {
+		other := filepath.Join(target, validator.ConfigName+"."+ext)
+		if _, err := os.Stat(other); err == nil && other != path {
+			log.Printf("warning: %s also exists, both will be loaded\n", other)
+		}
+	}
+
+	out, err := commentedConfig(cfg, set, *format)
+	if err != nil {
+		log.Println(err)
+		os.Exit(int(state.UndefindedError))
+	}
+
+	if err := ioutil.WriteFile(path, out, 0644); err != nil {
+		log.Println(err)
+		os.Exit(1)
+	}
+	log.Println("config written to", path)
+}
+
+// initConfig returns the config of the preset on top of the defaults, with the scopes
+// detected from root if the preset has none, along with the keys set by them
+func initConfig(preset *validator.Config, root string) (*validator.Config, map[string]bool) {
+	set := make(map[string]bool)
+	for _, item := range preset.Items() {
+		if !reflect.ValueOf(item.Value).IsZero() {
+			set[item.Key] = true
+		}
+	}
+
+	cfg := validator.NewConfig().Merge(preset)
+	if len(cfg.Scopes) == 0 {
+		if cfg.Scopes = detectScopes(root); len(cfg.Scopes) > 0 {
+			set["scopes"] = true
+		}
+	}
+	return cfg, set
+}
+
+func presetNames() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// detectScopes takes the top level directories of root as scopes,
+// except the hidden ones and those in skipDirs
+func detectScopes(root string) []string {
+	infos, err := ioutil.ReadDir(root)
+	if err != nil {
+		return nil
+	}
+
+	var scopes []string
+	for _, fi := range infos {
+		name := fi.Name()
+		if fi.IsDir() && !strings.HasPrefix(name, ".") && !skipDirs[name] {
+			scopes = append(scopes, name)
+		}
+	}
+	return scopes
+}
+
+// prompt asks for the values of the basic config items, empty input keeps the value,
+// the keys answered are added to set
+func prompt(cfg *validator.Config, set map[string]bool, in *bufio.Scanner) {
+	ask := func(question, key string, value interface{}) (string, bool) {
+		fmt.Printf("%s (%s) [%v]: ", question, key, value)
+		if !in.Scan() {
+			return "", false
+		}
+		answer := strings.TrimSpace(in.Text())
+		return answer, answer != ""
+	}
+
+	askBool := func(question, key string, value *bool) {
+		for {
+			answer, ok := ask(question, key, *value)
+			if !ok {
+				return
+			}
+			switch strings.ToLower(answer) {
+			case "y", "yes", "true":
+				*value, set[key] = true, true
+				return
+			case "n", "no", "false":
+				*value, set[key] = false, true
+				return
+			}
+			fmt.Println("please answer yes or no")
+		}
+	}
+
+	if answer, ok := ask("prompt language", "lang", cfg.Lang); ok {
+		cfg.Lang, set["lang"] = answer, true
+	}
+
+	for {
+		answer, ok := ask("length limit of lines, 0 to skip", "lineLimit", cfg.LineLimit)
+		if !ok {
+			break
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 0 {
+			cfg.LineLimit, set["lineLimit"] = n, true
+			break
+		}
+		fmt.Println("please answer a non-negative integer")
+	}
+
+	askBool("require message body", "bodyRequired", &cfg.BodyRequired)
+	askBool("require scope", "scopeRequired", &cfg.ScopeRequired)
+
+	if answer, ok := ask("allowed scopes, separated by comma, - for any", "scopes", strings.Join(cfg.Scopes, ", ")); ok {
+		cfg.Scopes, set["scopes"] = nil, true
+		if answer != "-" {
+			for _, s := range strings.Split(answer, ",") {
+				if s = strings.TrimSpace(s); s != "" {
+					cfg.Scopes = append(cfg.Scopes, s)
+				}
+			}
+		}
+	}
+}
+
+// commentedConfig encodes the config in format, with a comment for each item.
+// Only the items in set are written as keys, the others are commented out,
+// as any key present overrides the upper configs, see validator.Config.Merge.
+// JSON allows no comments, so only the items in set are written.
+func commentedConfig(cfg *validator.Config, set map[string]bool, format string) ([]byte, error) {
+	if format == "json" {
+		var live []validator.Item
+		for _, item := range items(cfg) {
+			if set[item.Key] {
+				live = append(live, item)
+			}
+		}
+		return itemsJSON(live)
+	}
+
+	assign := ": "
+	if format == "toml" {
+		assign = " = "
+	}
+
+	var b strings.Builder
+	b.WriteString("# commit-msg config, see https://github.com/JayceChant/commit-msg\n")
+	for _, item := range items(cfg) {
+		v, err := inlineValue(item.Value, format == "toml")
+		if err != nil {
+			return nil, err
+		}
+
+		b.WriteByte('\n')
+		if c := itemComments[item.Key]; c != "" {
+			fmt.Fprintf(&b, "# %s\n", c)
+		}
+		if !set[item.Key] {
+			b.WriteString("# ")
+		}
+		fmt.Fprintf(&b, "%s%s%s\n", item.Key, assign, v)
+	}
+	return []byte(b.String()), nil
+}
+
+// inlineValue encodes v as JSON in a single line, as a JSON scalar
+// or string array is a valid value in both YAML and TOML,
+// except that a map is written as an inline table in TOML
+func inlineValue(v interface{}, toml bool) (string, error) {
+	if m := reflect.ValueOf(v); m.Kind() == reflect.Map && toml {
+		entries := make([]string, 0, m.Len())
+		for _, k := range m.MapKeys() {
+			b, err := json.Marshal(m.MapIndex(k).Interface())
+			if err != nil {
+				return "", err
+			}
+			key, _ := json.Marshal(k.Interface())
+			entries = append(entries, fmt.Sprintf("%s = %s", key, b))
+		}
+		if len(entries) == 0 {
+			return "{}", nil
+		}
+		sort.Strings(entries)
+		return "{ " + strings.Join(entries, ", ") + " }", nil
+	}
+
+	list, ok := v.([]string)
+	if !ok {
+		b, err := json.Marshal(v)
+		return string(b), err
+	}
+
+	quoted := make([]string, len(list))
+	for i, s := range list {
+		b, _ := json.Marshal(s)
+		quoted[i] = string(b)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]", nil
+}
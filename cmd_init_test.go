@@ -0,0 +1,52 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/JayceChant/commit-msg/validator"
+)
+
+func TestCommentedConfig(t *testing.T) {
+	root, err := ioutil.TempDir("", "commit-msg-init")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	for _, d := range []string{"api", "web", "vendor", ".github"} {
+		os.Mkdir(filepath.Join(root, d), 0755)
+	}
+	global := filepath.Join(root, "global.json")
+	if err := ioutil.WriteFile(global, []byte(`{"lang": "zh", "types": ["wip"], "bodyRequired": true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, set := initConfig(presets["conventional"], root)
+	for _, format := range []string{"json", "yaml", "toml"} {
+		out, err := commentedConfig(cfg, set, format)
+		if err != nil {
+			t.Fatalf("%s: commentedConfig error %v", format, err)
+		}
+		path := filepath.Join(root, validator.ConfigName+"."+format)
+		if err := ioutil.WriteFile(path, out, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := validator.LoadConfig(global, path)
+		if err != nil {
+			t.Fatalf("%s: LoadConfig error %v\n%s", format, err, out)
+		}
+		// inherited from the global config
+		if got.Lang != "zh" || !got.BodyRequired || !reflect.DeepEqual(got.Types, []string{"wip"}) {
+			t.Errorf("%s: global config overridden, got lang %q, bodyRequired %v, types %v", format, got.Lang, got.BodyRequired, got.Types)
+		}
+		// set by the preset and detected
+		if got.LineLimit != 100 || !reflect.DeepEqual(got.DenyTypes, []string{"docker"}) || !reflect.DeepEqual(got.Scopes, []string{"api", "web"}) {
+			t.Errorf("%s: got lineLimit %d, denyTypes %v, scopes %v", format, got.LineLimit, got.DenyTypes, got.Scopes)
+		}
+	}
+}
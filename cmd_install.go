@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/JayceChant/commit-msg/dir"
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	hookName = "commit-msg"
+	// previousSuffix is appended to the name of the hook existing before install
+	previousSuffix = ".previous"
+	// hookMarker marks the hook script written by install
+	hookMarker = "# installed by commit-msg"
+	// modulePath is found in the binary, which tells an old-style install
+	// by copying the binary as the hook
+	modulePath = "github.com/JayceChant/commit-msg"
+	// defaultTemplateDir is used by install -global if init.templateDir is not set
+	defaultTemplateDir = "~/.git-templates"
+
+	hookScript = `#!/bin/sh
+%s, remove it by "commit-msg uninstall"
+# the hook existing before install is kept and run first
+previous="$(dirname "$0")/%s"
+if [ -x "$previous" ]; then
+	"$previous" "$@" || exit $?
+fi
+exec %s "$@"
+`
+)
+
+// runInstall writes the hook script into the hooks dir of the repository,
+// or of the template dir with -global, chaining with the existing hook
+func runInstall(args []string) {
+	fs := flag.NewFlagSet("install", flag.ExitOnError)
+	global := fs.Bool("global", false, "install into the hooks of init.templateDir, for the repositories cloned or initialized later")
+	force := fs.Bool("force", false, "replace the kept previous hook, if the existing hook is to be kept as well")
+	fs.Parse(args)
+
+	hooksDir, err := installDir(*global, true)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		log.Fatalln(err)
+	}
+
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		log.Fatalln(err)
+	}
+
+	hook := filepath.Join(hooksDir, hookName)
+	previous := hook + previousSuffix
+	if isForeignHook(hook) {
+		if _, err := os.Stat(previous); err == nil && !*force {
+			log.Fatalf("both %s and %s exist, use -force to replace the latter with the former\n", hook, previous)
+		}
+		if err := os.Rename(hook, previous); err != nil {
+			log.Fatalln(err)
+		}
+		log.Printf("existing hook kept as %s, and run before commit-msg\n", previous)
+	}
+
+	script := fmt.Sprintf(hookScript, hookMarker, hookName+previousSuffix, shQuote(filepath.ToSlash(exe)))
+	if err := ioutil.WriteFile(hook, []byte(script), 0755); err != nil {
+		log.Fatalln(err)
+	}
+	// WriteFile keeps the mode of an existing file
+	if err := os.Chmod(hook, 0755); err != nil {
+		log.Fatalln(err)
+	}
+	log.Println("hook installed to", hook)
+}
+
+// runUninstall removes the hook script written by install,
+// and restores the hook existing before install
+func runUninstall(args []string) {
+	fs := flag.NewFlagSet("uninstall", flag.ExitOnError)
+	global := fs.Bool("global", false, "uninstall from the hooks of init.templateDir")
+	fs.Parse(args)
+
+	hooksDir, err := installDir(*global, false)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	hook := filepath.Join(hooksDir, hookName)
+	previous := hook + previousSuffix
+	if _, err := os.Stat(hook); err != nil {
+		log.Fatalf("%s not found, nothing to uninstall\n", hook)
+	}
+	if isForeignHook(hook) {
+		log.Fatalf("%s is not installed by commit-msg, leave it untouched\n", hook)
+	}
+
+	if err := os.Remove(hook); err != nil {
+		log.Fatalln(err)
+	}
+
+	if _, err := os.Stat(previous); err == nil {
+		if err := os.Rename(previous, hook); err != nil {
+			log.Fatalln(err)
+		}
+		log.Println("previous hook restored to", hook)
+		return
+	}
+	log.Println("hook removed from", hook)
+}
+
+// installDir returns the hooks dir of the current repository, honouring
+// core.hooksPath, worktrees and submodules, or the hooks dir in init.templateDir
+// if global is true, which is set to defaultTemplateDir if not set and setDefault is true.
+func installDir(global, setDefault bool) (string, error) {
+	if !global {
+		repo, err := dir.Locate()
+		if err != nil {
+			return "", err
+		}
+		return repo.HooksDir, nil
+	}
+
+	if hooksPath, _ := dir.GitOutput("config", "--global", "core.hooksPath"); hooksPath != "" {
+		log.Printf("warning: core.hooksPath is set to %s globally, the hooks of init.templateDir are ignored by git\n", hooksPath)
+	}
+
+	templateDir, _ := dir.GitOutput("config", "--global", "--path", "init.templateDir")
+	if templateDir == "" {
+		if !setDefault {
+			return "", fmt.Errorf("init.templateDir is not set")
+		}
+
+		templateDir = defaultTemplateDir
+		if _, err := dir.GitOutput("config", "--global", "init.templateDir", templateDir); err != nil {
+			return "", err
+		}
+		log.Println("init.templateDir is set to", templateDir)
+	}
+
+	templateDir, err := homedir.Expand(templateDir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(templateDir, "hooks"), nil
+}
+
+// shQuote quotes s as a single word for sh, even if it contains quotes, $ or backquotes
+func shQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// isForeignHook returns if hook exists and is neither written by install,
+// nor the binary itself copied as the hook
+func isForeignHook(hook string) bool {
+	buf, err := ioutil.ReadFile(hook)
+	if err != nil {
+		return false
+	}
+	return !bytes.Contains(buf, []byte(hookMarker)) && !bytes.Contains(buf, []byte(modulePath))
+}
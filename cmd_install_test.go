@@ -0,0 +1,82 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInstall(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	root, err := ioutil.TempDir("", "commit-msg-install")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	// keep off the global hooksPath of the user
+	defer os.Setenv("HOME", os.Getenv("HOME"))
+	os.Setenv("HOME", root)
+	cwd, _ := os.Getwd()
+	defer os.Chdir(cwd)
+	os.Chdir(root)
+
+	if out, err := exec.Command("git", "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v, %s", err, out)
+	}
+	hook := filepath.Join(root, ".git", "hooks", hookName)
+	previous := hook + previousSuffix
+	foreign := "#!/bin/sh\necho foreign\n"
+	os.MkdirAll(filepath.Dir(hook), 0755)
+	if err := ioutil.WriteFile(hook, []byte(foreign), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	read := func(path string) string {
+		buf, err := ioutil.ReadFile(path)
+		if err != nil {
+			return ""
+		}
+		return string(buf)
+	}
+
+	// the foreign hook is kept and chained
+	runInstall(nil)
+	if !strings.Contains(read(hook), hookMarker) || read(previous) != foreign {
+		t.Fatalf("install got hook %q, previous %q", read(hook), read(previous))
+	}
+
+	// reinstall keeps the previous hook as is
+	runInstall(nil)
+	if !strings.Contains(read(hook), hookMarker) || read(previous) != foreign {
+		t.Fatalf("reinstall got hook %q, previous %q", read(hook), read(previous))
+	}
+
+	// uninstall restores the foreign hook
+	runUninstall(nil)
+	if read(hook) != foreign {
+		t.Errorf("uninstall got hook %q, want %q", read(hook), foreign)
+	}
+	if _, err := os.Stat(previous); !os.IsNotExist(err) {
+		t.Errorf("uninstall left %s", previous)
+	}
+}
+
+func TestShQuote(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not found")
+	}
+
+	for _, s := range []string{"/usr/bin/commit-msg", "/home/it's me/bin", `C:/a "b"/$HOME/` + "`id`"} {
+		out, err := exec.Command("sh", "-c", "printf %s "+shQuote(s)).Output()
+		if err != nil || string(out) != s {
+			t.Errorf("shQuote(%q) got %q, %v", s, out, err)
+		}
+	}
+}
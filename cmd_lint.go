@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/JayceChant/commit-msg/dir"
+	"github.com/JayceChant/commit-msg/state"
+	"github.com/JayceChant/commit-msg/validator"
+)
+
+const (
+	// logFormat prints hash, author and raw message of each commit,
+	// separated by newline, and commits separated by NUL with -z
+	logFormat = "--format=%H%n%an <%ae>%n%B"
+)
+
+// commit is a commit in the history to lint
+type commit struct {
+	Hash   string
+	Author string
+	Msg    string
+}
+
+// subject returns the first line of the message
+func (c *commit) subject() string {
+	return strings.SplitN(c.Msg, "\n", 2)[0]
+}
+
+// runLint validates the messages of a range of commits in the history
+func runLint(args []string) {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	from := fs.String("from", "", "lint the commits after this revision, exclusive")
+	to := fs.String("to", "HEAD", "lint the commits up to this revision, inclusive")
+	last := fs.Int("last", 0, "lint the last N commits up to -to, instead of -from")
+	format := fs.String("format", defaultFormat(), "output format: "+formatNames)
+	fs.Parse(args)
+	checkFormat(*format)
+
+	if (*from == "") == (*last <= 0) {
+		log.Println("either -from or -last is required")
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := validator.LoadCurrentConfig()
+	if err != nil {
+		state.ConfigError.LogAndExit(err)
+	}
+
+	commits, err := listCommits(*from, *to, *last)
+	if err != nil {
+		log.Println(err)
+		os.Exit(int(state.ReadError))
+	}
+
+	r := report{format: *format}
+	for _, c := range commits {
+		res, err := validator.Check(c.Msg, *commitConfig(cfg, c.Hash))
+		if err != nil {
+			state.ConfigError.LogAndExit(err)
+		}
+		r.add(fmt.Sprintf("%s %s %s", c.Hash[:7], c.Author, c.subject()), c.Hash, res)
+	}
+	r.exit("commits")
+}
+
+// commitConfig returns cfg with the files changed by the commit if ScopePaths is set,
+// see validator.Config.ForFiles
+func commitConfig(cfg *validator.Config, hash string) *validator.Config {
+	if len(cfg.ScopePaths) == 0 {
+		return cfg
+	}
+	files, err := dir.CommitFiles(hash)
+	if err != nil {
+		log.Println(err)
+		os.Exit(int(state.ReadError))
+	}
+	return cfg.ForFiles(files)
+}
+
+// listCommits lists the commits in from..to, or the last N commits up to to,
+// from the newest to the oldest
+func listCommits(from, to string, last int) ([]*commit, error) {
+	args := []string{"log", "-z", logFormat}
+	if last > 0 {
+		args = append(args, "-n", strconv.Itoa(last), to)
+	} else {
+		args = append(args, from+".."+to)
+	}
+	// separate revisions from paths
+	args = append(args, "--")
+
+	out, err := dir.GitOutput(args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseCommits(out)
+}
+
+// parseCommits parses the output of git log in logFormat with -z
+func parseCommits(out string) ([]*commit, error) {
+	var commits []*commit
+	for _, record := range strings.Split(out, "\x00") {
+		if record == "" {
+			continue
+		}
+		fields := strings.SplitN(record, "\n", 3)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("unexpected output of git log: %q", record)
+		}
+		commits = append(commits, &commit{Hash: fields[0], Author: fields[1], Msg: fields[2]})
+	}
+	return commits, nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCommits(t *testing.T) {
+	var commitCases = []struct {
+		name    string
+		out     string
+		want    []*commit
+		wantErr bool
+	}{
+		{"empty", "", nil, false},
+		{"single", "abc\nAlice <a@example.com>\nfeat: add x\n", []*commit{{"abc", "Alice <a@example.com>", "feat: add x\n"}}, false},
+		{
+			"multiple",
+			"abc\nAlice <a@example.com>\nfeat: add x\n\nbody\nmore body\n\x00def\nBob <b@example.com>\nfix: y\n\x00",
+			[]*commit{
+				{"abc", "Alice <a@example.com>", "feat: add x\n\nbody\nmore body\n"},
+				{"def", "Bob <b@example.com>", "fix: y\n"},
+			},
+			false,
+		},
+		{"empty_message", "abc\nAlice <a@example.com>\n", []*commit{{"abc", "Alice <a@example.com>", ""}}, false},
+		{"truncated", "abc\nAlice <a@example.com>", nil, true},
+	}
+	for _, tt := range commitCases {
+		got, err := parseCommits(tt.out)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: parseCommits error %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: parseCommits got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -11,40 +11,68 @@ const (
 	hookDir = "./.git/hooks/"
 )
 
-func FindFiles(file string) []string {
-	paths := make([]string, 0)
-	if home, err := homedir.Dir(); err == nil {
-		paths = append(paths, filepath.Join(home, file))
+// RootDir returns the root of the working tree, where the project config is placed,
+// or the working directory if not in a git repository.
+func RootDir() string {
+	if repo := Current(); repo != nil && repo.WorkTree != "" {
+		return repo.WorkTree
 	}
+	return "."
+}
 
-	f, err := os.Stat(hookDir)
-	if (err == nil || os.IsExist(err)) && f.IsDir() {
-		// working dir is on project root
-		paths = append(paths, filepath.Join(hookDir, file))
-	} else {
-		// work around for test
-		paths = append(paths, file)
+// HooksDir returns the hooks dir of the repository, where the local config is placed.
+func HooksDir() string {
+	if repo := Current(); repo != nil {
+		return repo.HooksDir
 	}
-	return paths
+	return hookDir
 }
 
-func FindFirstExist(file string) string {
-	if isFile(file) {
-		return file
+// Branch returns the branch checked out, or "" if HEAD is detached or not in a git repository.
+func Branch() string {
+	if repo := Current(); repo != nil {
+		return repo.Branch()
 	}
+	return ""
+}
 
-	hookFile := filepath.Join(hookDir, file)
-	if isFile(hookFile) {
-		return hookFile
+// FindFiles returns the paths where files may be placed,
+// in the order of precedence from low to high, files in the same place
+// in the order given:
+//  1. home dir, the global file of the user
+//  2. repository root, the file committed with the project
+//  3. hooks dir, the local file of the clone, never committed
+func FindFiles(files ...string) []string {
+	dirs := make([]string, 0, 3)
+	if home, err := homedir.Dir(); err == nil {
+		dirs = append(dirs, home)
 	}
 
-	if home, err := homedir.Dir(); err == nil {
-		homeFile := filepath.Join(home, file)
-		if isFile(homeFile) {
-			return homeFile
+	dirs = append(dirs, RootDir())
+
+	f, err := os.Stat(HooksDir())
+	if (err == nil || os.IsExist(err)) && f.IsDir() {
+		dirs = append(dirs, HooksDir())
+	}
+
+	paths := make([]string, 0, len(dirs)*len(files))
+	for _, d := range dirs {
+		for _, file := range files {
+			paths = append(paths, filepath.Join(d, file))
 		}
 	}
+	return paths
+}
 
+// FindFirstExist returns the existing path of file with the highest precedence,
+// see FindFiles, or "" if none exists.
+func FindFirstExist(file string) string {
+	paths := FindFiles(file)
+	for i := len(paths) - 1; i >= 0; i-- {
+		if isFile(paths[i]) {
+			return paths[i]
+		}
+	}
 	return ""
 }
 
@@ -0,0 +1,316 @@
+package dir
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+// Repo is the location of a git repository
+type Repo struct {
+	// WorkTree is the root of the working tree, "" for a bare repository
+	WorkTree string
+	// GitDir is the git dir of the working tree, e.g. .git,
+	// or .git/worktrees/<name> for a linked worktree
+	GitDir string
+	// CommonDir is the git dir shared by all the worktrees, e.g. .git
+	CommonDir string
+	// HooksDir is where git looks for hooks, honouring core.hooksPath
+	HooksDir string
+}
+
+var (
+	// ErrNotRepo is returned by Locate outside of any git repository
+	ErrNotRepo = errors.New("not a git repository")
+
+	current     *Repo
+	currentOnce sync.Once
+)
+
+// Current returns the repository of the working directory, located once and cached,
+// or nil if the working directory is not in a git repository.
+func Current() *Repo {
+	currentOnce.Do(func() {
+		current, _ = Locate()
+	})
+	return current
+}
+
+// Locate finds the git repository of the working directory, the same way git does,
+// by asking git itself. If git is not available, it falls back to LocateFrom.
+func Locate() (*Repo, error) {
+	out, err := GitOutput("rev-parse", "--git-dir", "--git-common-dir", "--git-path", "hooks")
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, ErrNotRepo
+		}
+		// git not found
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		return LocateFrom(cwd)
+	}
+
+	lines := strings.Split(out, "\n")
+	if len(lines) != 3 {
+		return nil, fmt.Errorf("unexpected output of git rev-parse: %q", out)
+	}
+
+	repo := &Repo{}
+	for i, p := range []*string{&repo.GitDir, &repo.CommonDir, &repo.HooksDir} {
+		if *p, err = filepath.Abs(lines[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	// fails in a bare repository, which has no working tree
+	repo.WorkTree, _ = GitOutput("rev-parse", "--show-toplevel")
+	return repo, nil
+}
+
+// LocateFrom finds the git repository of dir without git, honouring GIT_DIR,
+// GIT_WORK_TREE and GIT_COMMON_DIR, the .git file of linked worktrees and submodules,
+// and core.hooksPath in the repository config. Other config files are not read.
+func LocateFrom(dir string) (*Repo, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := &Repo{}
+	if gitDir := os.Getenv("GIT_DIR"); gitDir != "" {
+		repo.GitDir = absFrom(dir, gitDir)
+		repo.WorkTree = dir
+		if workTree := os.Getenv("GIT_WORK_TREE"); workTree != "" {
+			repo.WorkTree = absFrom(dir, workTree)
+		}
+	} else if repo.WorkTree, repo.GitDir, err = findGitDir(dir); err != nil {
+		return nil, err
+	}
+
+	repo.CommonDir = repo.GitDir
+	if commonDir := os.Getenv("GIT_COMMON_DIR"); commonDir != "" {
+		repo.CommonDir = absFrom(dir, commonDir)
+	} else if buf, err := ioutil.ReadFile(filepath.Join(repo.GitDir, "commondir")); err == nil {
+		repo.CommonDir = absFrom(repo.GitDir, strings.TrimSpace(string(buf)))
+	}
+
+	repo.HooksDir = filepath.Join(repo.CommonDir, "hooks")
+	if hooksPath := configValue(filepath.Join(repo.CommonDir, "config"), "core", "hookspath"); hooksPath != "" {
+		if hooksPath, err = homedir.Expand(hooksPath); err != nil {
+			return nil, err
+		}
+		// relative to where the hooks are run, the root of the working tree
+		base := repo.WorkTree
+		if base == "" {
+			base = repo.GitDir
+		}
+		repo.HooksDir = absFrom(base, hooksPath)
+	}
+	return repo, nil
+}
+
+// findGitDir walks up from dir to find .git, which is either the git dir,
+// or a file telling the path of the git dir for linked worktrees and submodules.
+// A directory looks like a git dir itself is taken as a bare repository.
+func findGitDir(dir string) (workTree, gitDir string, err error) {
+	for {
+		dotGit := filepath.Join(dir, ".git")
+		if fi, err := os.Stat(dotGit); err == nil {
+			if fi.IsDir() {
+				return dir, dotGit, nil
+			}
+
+			buf, err := ioutil.ReadFile(dotGit)
+			if err != nil {
+				return "", "", err
+			}
+			content := strings.TrimSpace(string(buf))
+			if !strings.HasPrefix(content, "gitdir:") {
+				return "", "", fmt.Errorf("invalid gitfile format: %s", dotGit)
+			}
+			return dir, absFrom(dir, strings.TrimSpace(strings.TrimPrefix(content, "gitdir:"))), nil
+		}
+
+		if isBareGitDir(dir) {
+			return "", dir, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", ErrNotRepo
+		}
+		dir = parent
+	}
+}
+
+func isBareGitDir(dir string) bool {
+	head, err := os.Stat(filepath.Join(dir, "HEAD"))
+	if err != nil || head.IsDir() {
+		return false
+	}
+	objects, err := os.Stat(filepath.Join(dir, "objects"))
+	return err == nil && objects.IsDir()
+}
+
+// absFrom returns path as is if absolute, otherwise joined to base
+func absFrom(base, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(base, path)
+}
+
+// configValue reads the value of key in section from a git config file,
+// the last one wins. Section and key are case-insensitive, subsections
+// and include directives are not supported.
+func configValue(file, section, key string) string {
+	f, err := os.Open(file)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	value, inSection := "", false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		if line[0] == '[' {
+			end := strings.IndexByte(line, ']')
+			inSection = end > 0 && strings.EqualFold(strings.TrimSpace(line[1:end]), section)
+			continue
+		}
+
+		if !inSection {
+			continue
+		}
+
+		eq := strings.IndexByte(line, '=')
+		if eq < 0 || !strings.EqualFold(strings.TrimSpace(line[:eq]), key) {
+			continue
+		}
+		value = unquote(strings.TrimSpace(line[eq+1:]))
+	}
+	return value
+}
+
+// unquote strips the quotes and the trailing comment of a git config value
+func unquote(value string) string {
+	var b strings.Builder
+	quoted := false
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case c == '"':
+			quoted = !quoted
+		case c == '\\' && i+1 < len(value):
+			i++
+			switch value[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(value[i])
+			}
+		case (c == '#' || c == ';') && !quoted:
+			return strings.TrimSpace(b.String())
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// branchPrefix is the prefix of the HEAD file on a branch
+const branchPrefix = "ref: refs/heads/"
+
+// Branch returns the branch checked out in the working tree, read from HEAD in the git dir,
+// or "" if HEAD is detached, e.g. during a rebase, or not readable.
+func (r *Repo) Branch() string {
+	buf, err := ioutil.ReadFile(filepath.Join(r.GitDir, "HEAD"))
+	if err != nil {
+		return ""
+	}
+
+	head := strings.TrimSpace(string(buf))
+	if !strings.HasPrefix(head, branchPrefix) {
+		return ""
+	}
+	return head[len(branchPrefix):]
+}
+
+// StagedFiles lists the paths of the files staged for the commit, relative to the root
+// of the working tree, honouring GIT_INDEX_FILE set by git commit -a or with paths.
+func StagedFiles() ([]string, error) {
+	return gitFiles("diff", "--cached", "--name-only", "-z")
+}
+
+// CommitFiles lists the paths of the files changed by the commit rev, relative to the root
+// of the working tree, none for a merge commit.
+func CommitFiles(rev string) ([]string, error) {
+	return gitFiles("diff-tree", "-r", "--root", "--no-commit-id", "--name-only", "-z", rev)
+}
+
+// gitFiles runs git with args, returns the NUL-separated paths in the output
+func gitFiles(args ...string) ([]string, error) {
+	out, err := GitOutput(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, f := range strings.Split(out, "\x00") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// GitError is the error running git, telling the message git prints to stderr
+type GitError struct {
+	Args   []string
+	Stderr string
+	// Err is *exec.ExitError if git fails, or the error starting git, e.g. not found
+	Err error
+}
+
+func (e *GitError) Error() string {
+	if e.Stderr != "" {
+		return fmt.Sprintf("git %s: %s", strings.Join(e.Args, " "), e.Stderr)
+	}
+	return fmt.Sprintf("git %s: %v", strings.Join(e.Args, " "), e.Err)
+}
+
+// Unwrap returns the underlying error, for errors.As
+func (e *GitError) Unwrap() error {
+	return e.Err
+}
+
+// GitOutput runs git with args in the working directory, returns the output trimmed,
+// or *GitError if git fails
+func GitOutput(args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", &GitError{Args: args, Stderr: strings.TrimSpace(stderr.String()), Err: err}
+	}
+	return strings.TrimSpace(string(out)), nil
+}
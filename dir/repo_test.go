@@ -0,0 +1,201 @@
+package dir
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// tempRepo creates a layout of git dirs for test, without git
+func tempRepo(t *testing.T) string {
+	root, err := ioutil.TempDir("", "commit-msg-repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// resolve symlinks in temp dir, e.g. /var -> /private/var on macOS
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"main/.git/HEAD":                      "ref: refs/heads/master\n",
+		"main/.git/config":                    "[core]\n\tbare = false\n",
+		"main/.git/worktrees/wt/HEAD":         "ref: refs/heads/feature\n",
+		"main/.git/worktrees/wt/commondir":    "../..\n",
+		"main/sub/dir/file.txt":               "",
+		"wt/.git":                             "gitdir: " + filepath.Join(root, "main/.git/worktrees/wt") + "\n",
+		"hooked/.git/HEAD":                    "ref: refs/heads/master\n",
+		"hooked/.git/config":                  "[core]\n\thooksPath = \"githooks\" # shared hooks\n",
+		"bare.git/HEAD":                       "ref: refs/heads/master\n",
+		"bare.git/objects/.keep":              "",
+		"main/modules/.git":                   "gitdir: ../.git/modules/mod\n",
+		"main/.git/modules/mod/HEAD":          "ref: refs/heads/master\n",
+		"main/.git/modules/mod/objects/.keep": "",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestLocateFrom(t *testing.T) {
+	os.Unsetenv("GIT_DIR")
+	os.Unsetenv("GIT_WORK_TREE")
+	os.Unsetenv("GIT_COMMON_DIR")
+
+	root := tempRepo(t)
+	defer os.RemoveAll(root)
+
+	p := func(name string) string {
+		if name == "" {
+			return ""
+		}
+		return filepath.Join(root, name)
+	}
+
+	var repoCases = []struct {
+		name string
+		dir  string
+		want Repo
+	}{
+		{"root", "main", Repo{p("main"), p("main/.git"), p("main/.git"), p("main/.git/hooks")}},
+		{"subdir", "main/sub/dir", Repo{p("main"), p("main/.git"), p("main/.git"), p("main/.git/hooks")}},
+		{"worktree", "wt", Repo{p("wt"), p("main/.git/worktrees/wt"), p("main/.git"), p("main/.git/hooks")}},
+		{"submodule", "main/modules", Repo{p("main/modules"), p("main/.git/modules/mod"), p("main/.git/modules/mod"), p("main/.git/modules/mod/hooks")}},
+		{"hooks_path", "hooked", Repo{p("hooked"), p("hooked/.git"), p("hooked/.git"), p("hooked/githooks")}},
+		{"bare", "bare.git", Repo{"", p("bare.git"), p("bare.git"), p("bare.git/hooks")}},
+	}
+	for _, tt := range repoCases {
+		got, err := LocateFrom(p(tt.dir))
+		if err != nil {
+			t.Errorf("%s: LocateFrom error %v", tt.name, err)
+			continue
+		}
+		if *got != tt.want {
+			t.Errorf("%s: LocateFrom got %+v, want %+v", tt.name, *got, tt.want)
+		}
+	}
+
+	if _, err := LocateFrom(os.TempDir()); err != ErrNotRepo {
+		t.Errorf("LocateFrom outside of repository, got error %v, want %v", err, ErrNotRepo)
+	}
+}
+
+func TestLocate(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	root, err := ioutil.TempDir("", "commit-msg-git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		t.Fatal(err)
+	}
+
+	cwd, _ := os.Getwd()
+	defer os.Chdir(cwd)
+	os.Chdir(root)
+
+	if err := exec.Command("git", "init", "-q").Run(); err != nil {
+		t.Fatal(err)
+	}
+	if err := exec.Command("git", "config", "core.hooksPath", ".githooks").Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Locate()
+	if err != nil {
+		t.Fatalf("Locate error %v", err)
+	}
+	want, err := LocateFrom(root)
+	if err != nil {
+		t.Fatalf("LocateFrom error %v", err)
+	}
+	if *got != *want {
+		t.Errorf("Locate got %+v, LocateFrom got %+v", *got, *want)
+	}
+}
+
+func TestBranch(t *testing.T) {
+	gitDir, err := ioutil.TempDir("", "commit-msg-head")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(gitDir)
+
+	var branchCases = []struct {
+		head string
+		want string
+	}{
+		{"ref: refs/heads/feature/PROJ-123-foo\n", "feature/PROJ-123-foo"},
+		{"ref: refs/heads/main", "main"},
+		{"0123456789abcdef0123456789abcdef01234567\n", ""},
+	}
+	repo := &Repo{GitDir: gitDir}
+	for _, tt := range branchCases {
+		if err := ioutil.WriteFile(filepath.Join(gitDir, "HEAD"), []byte(tt.head), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if got := repo.Branch(); got != tt.want {
+			t.Errorf("Branch of HEAD %q got %q, want %q", tt.head, got, tt.want)
+		}
+	}
+
+	if got := (&Repo{GitDir: filepath.Join(gitDir, "missing")}).Branch(); got != "" {
+		t.Errorf("Branch without HEAD got %q", got)
+	}
+}
+
+func TestStagedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+
+	root, err := ioutil.TempDir("", "commit-msg-staged")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	cwd, _ := os.Getwd()
+	defer os.Chdir(cwd)
+	os.Chdir(root)
+
+	git := func(args ...string) {
+		args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v, %s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	for _, name := range []string{"services/billing/main.go", "web/app.js", "README.md"} {
+		os.MkdirAll(filepath.Dir(name), 0755)
+		ioutil.WriteFile(name, []byte(name), 0644)
+	}
+	git("add", "services", "web")
+
+	want := []string{"services/billing/main.go", "web/app.js"}
+	if got, err := StagedFiles(); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("StagedFiles got %v, %v, want %v", got, err, want)
+	}
+
+	git("commit", "-q", "-m", "feat: init")
+	if got, err := CommitFiles("HEAD"); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("CommitFiles got %v, %v, want %v", got, err, want)
+	}
+	if got, err := StagedFiles(); err != nil || len(got) != 0 {
+		t.Errorf("StagedFiles after commit got %v, %v, want none", got, err)
+	}
+}
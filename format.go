@@ -0,0 +1,300 @@
+package main
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/JayceChant/commit-msg/state"
+	"github.com/JayceChant/commit-msg/validator"
+)
+
+const (
+	toolName = "commit-msg"
+	toolURI  = "https://github.com/JayceChant/commit-msg"
+)
+
+// jsonReport lists the messages with their states and issues
+func jsonReport(entries []*entry) (string, error) {
+	type jsonEntry struct {
+		Path     string      `json:"path"`
+		Title    string      `json:"title"`
+		State    state.State `json:"state"`
+		Breaking bool        `json:"breaking"`
+		Issues   []issue     `json:"issues"`
+	}
+
+	list := make([]jsonEntry, 0, len(entries))
+	for _, e := range entries {
+		issues := e.issues
+		if issues == nil {
+			issues = []issue{}
+		}
+		list = append(list, jsonEntry{Path: e.path, Title: e.title, State: e.state, Breaking: e.breaking, Issues: issues})
+	}
+	return marshalJSON(list)
+}
+
+func marshalJSON(v interface{}) (string, error) {
+	buf, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(buf) + "\n", nil
+}
+
+// sarifReport writes a SARIF 2.1.0 log for code scanning,
+// with a rule for each state reported
+func sarifReport(entries []*entry) (string, error) {
+	type message struct {
+		Text string `json:"text"`
+	}
+	type region struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn,omitempty"`
+	}
+	type physicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region *region `json:"region,omitempty"`
+	}
+	type location struct {
+		PhysicalLocation physicalLocation `json:"physicalLocation"`
+	}
+	type result struct {
+		RuleID    string     `json:"ruleId"`
+		Level     string     `json:"level"`
+		Message   message    `json:"message"`
+		Locations []location `json:"locations"`
+	}
+	type rule struct {
+		ID               string  `json:"id"`
+		ShortDescription message `json:"shortDescription"`
+	}
+	type driver struct {
+		Name           string `json:"name"`
+		Version        string `json:"version,omitempty"`
+		InformationURI string `json:"informationUri"`
+		Rules          []rule `json:"rules"`
+	}
+	type run struct {
+		Tool struct {
+			Driver driver `json:"driver"`
+		} `json:"tool"`
+		Results []result `json:"results"`
+	}
+	type sarifLog struct {
+		Schema  string `json:"$schema"`
+		Version string `json:"version"`
+		Runs    []run  `json:"runs"`
+	}
+
+	var r run
+	r.Tool.Driver = driver{Name: toolName, Version: version, InformationURI: toolURI, Rules: []rule{}}
+	r.Results = []result{}
+	ruled := make(map[state.State]bool)
+	for _, e := range entries {
+		for _, is := range e.issues {
+			if !ruled[is.State] {
+				ruled[is.State] = true
+				r.Tool.Driver.Rules = append(r.Tool.Driver.Rules, rule{ID: is.State.String(), ShortDescription: message{is.State.String()}})
+			}
+
+			var loc location
+			loc.PhysicalLocation.ArtifactLocation.URI = e.path
+			if is.Line > 0 {
+				loc.PhysicalLocation.Region = &region{StartLine: is.Line, StartColumn: is.Column}
+			}
+			r.Results = append(r.Results, result{
+				RuleID:    is.State.String(),
+				Level:     is.Severity,
+				Message:   message{is.Message},
+				Locations: []location{loc},
+			})
+		}
+	}
+
+	return marshalJSON(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []run{r},
+	})
+}
+
+// junitReport writes a JUnit XML test report, a test case for each message,
+// failed with the errors of it, while the warnings are printed as the output
+func junitReport(entries []*entry) (string, error) {
+	type failure struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr"`
+		Text    string `xml:",chardata"`
+	}
+	type testCase struct {
+		Name      string    `xml:"name,attr"`
+		ClassName string    `xml:"classname,attr"`
+		Failures  []failure `xml:"failure"`
+		SystemOut string    `xml:"system-out,omitempty"`
+	}
+	type testSuite struct {
+		XMLName  xml.Name   `xml:"testsuite"`
+		Name     string     `xml:"name,attr"`
+		Tests    int        `xml:"tests,attr"`
+		Failures int        `xml:"failures,attr"`
+		Cases    []testCase `xml:"testcase"`
+	}
+	type testSuites struct {
+		XMLName xml.Name    `xml:"testsuites"`
+		Suites  []testSuite `xml:"testsuite"`
+	}
+
+	suite := testSuite{Name: toolName, Tests: len(entries)}
+	for _, e := range entries {
+		tc := testCase{Name: e.title, ClassName: toolName}
+		for _, is := range e.issues {
+			text := fmt.Sprintf("%s:%d:%d: %s", e.path, is.Line, is.Column, is.Message)
+			if is.Severity == string(validator.SeverityWarning) {
+				tc.SystemOut += text + "\n"
+				continue
+			}
+			tc.Failures = append(tc.Failures, failure{
+				Message: firstLine(is.Message),
+				Type:    is.State.String(),
+				Text:    text,
+			})
+		}
+		if e.failed() {
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	return marshalXML(testSuites{Suites: []testSuite{suite}})
+}
+
+// checkstyleReport writes a Checkstyle XML report, a file for each message
+func checkstyleReport(entries []*entry) (string, error) {
+	type checkError struct {
+		Line     int    `xml:"line,attr"`
+		Column   int    `xml:"column,attr,omitempty"`
+		Severity string `xml:"severity,attr"`
+		Message  string `xml:"message,attr"`
+		Source   string `xml:"source,attr"`
+	}
+	type file struct {
+		Name   string       `xml:"name,attr"`
+		Errors []checkError `xml:"error"`
+	}
+	type checkstyle struct {
+		XMLName xml.Name `xml:"checkstyle"`
+		Version string   `xml:"version,attr"`
+		Files   []file   `xml:"file"`
+	}
+
+	cs := checkstyle{Version: "4.3"}
+	for _, e := range entries {
+		f := file{Name: e.path}
+		for _, is := range e.issues {
+			f.Errors = append(f.Errors, checkError{
+				Line:     is.Line,
+				Column:   is.Column,
+				Severity: is.Severity,
+				Message:  is.Message,
+				Source:   toolName + "." + is.State.String(),
+			})
+		}
+		cs.Files = append(cs.Files, f)
+	}
+	return marshalXML(cs)
+}
+
+// githubReport prints the workflow commands of GitHub Actions,
+// which annotate the issues inline on the pull request
+func githubReport(entries []*entry) (string, error) {
+	var b strings.Builder
+	for _, e := range entries {
+		for _, is := range e.issues {
+			props := []string{"file=" + escapeProperty(e.path)}
+			if is.Line > 0 {
+				props = append(props, fmt.Sprintf("line=%d", is.Line))
+			}
+			if is.Column > 0 {
+				props = append(props, fmt.Sprintf("col=%d", is.Column))
+			}
+			props = append(props, "title="+escapeProperty(toolName+" "+is.State.String()))
+			fmt.Fprintf(&b, "::%s %s::%s\n", is.Severity, strings.Join(props, ","), escapeData(is.Message))
+		}
+	}
+	return b.String(), nil
+}
+
+var (
+	dataEscaper     = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	propertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+func escapeData(s string) string {
+	return dataEscaper.Replace(s)
+}
+
+func escapeProperty(s string) string {
+	return propertyEscaper.Replace(s)
+}
+
+// gitlabSeverities maps the severities to those of GitLab code quality
+var gitlabSeverities = map[string]string{
+	"error":   "major",
+	"warning": "minor",
+}
+
+// gitlabReport writes a GitLab code quality report
+func gitlabReport(entries []*entry) (string, error) {
+	type lines struct {
+		Begin int `json:"begin"`
+	}
+	type location struct {
+		Path  string `json:"path"`
+		Lines lines  `json:"lines"`
+	}
+	type codeQuality struct {
+		Description string   `json:"description"`
+		CheckName   string   `json:"check_name"`
+		Fingerprint string   `json:"fingerprint"`
+		Severity    string   `json:"severity"`
+		Location    location `json:"location"`
+	}
+
+	list := []codeQuality{}
+	for _, e := range entries {
+		for _, is := range e.issues {
+			line := is.Line
+			if line <= 0 {
+				line = 1
+			}
+			sum := md5.Sum([]byte(fmt.Sprintf("%s:%d:%d:%s", e.path, is.Line, is.Column, is.State)))
+			list = append(list, codeQuality{
+				Description: is.Message,
+				CheckName:   is.State.String(),
+				Fingerprint: hex.EncodeToString(sum[:]),
+				Severity:    gitlabSeverities[is.Severity],
+				Location:    location{Path: e.path, Lines: lines{Begin: line}},
+			})
+		}
+	}
+	return marshalJSON(list)
+}
+
+func marshalXML(v interface{}) (string, error) {
+	buf, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(buf) + "\n", nil
+}
+
+// firstLine returns the first line of a multi-line hint
+func firstLine(s string) string {
+	return strings.SplitN(s, "\n", 2)[0]
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/JayceChant/commit-msg/state"
+	"github.com/JayceChant/commit-msg/validator"
+)
+
+func TestFormats(t *testing.T) {
+	cfg := validator.NewConfig()
+	var r report
+	for _, msg := range []string{"feat: ok", "bad: type\nbody"} {
+		res, err := validator.Check(msg, *cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.add(msg, "msg.txt", res)
+	}
+
+	for name, f := range formats {
+		out, err := f(r.entries)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+
+		switch name {
+		case "json", "sarif":
+			var v interface{}
+			err = json.Unmarshal([]byte(out), &v)
+		case "junit", "checkstyle":
+			err = xml.Unmarshal([]byte(out), new(struct{ XMLName xml.Name }))
+		}
+		if err != nil {
+			t.Errorf("%s: invalid output %v:\n%s", name, err, out)
+		}
+
+		for _, s := range []state.State{state.WrongType, state.NoBlankLineBeforeBody} {
+			if !strings.Contains(out, s.String()) {
+				t.Errorf("%s: %v not reported:\n%s", name, s, out)
+			}
+		}
+	}
+}
+
+func TestEscapeGitHub(t *testing.T) {
+	if got, want := escapeData("50%\nmore"), "50%25%0Amore"; got != want {
+		t.Errorf("escapeData got %q, expected %q", got, want)
+	}
+	if got, want := escapeProperty("a:b,c"), "a%3Ab%2Cc"; got != want {
+		t.Errorf("escapeProperty got %q, expected %q", got, want)
+	}
+}
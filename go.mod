@@ -2,4 +2,8 @@ module github.com/JayceChant/commit-msg
 
 go 1.13
 
-require github.com/mitchellh/go-homedir v1.1.0
+require (
+	github.com/BurntSushi/toml v1.3.2
+	github.com/mitchellh/go-homedir v1.1.0
+	gopkg.in/yaml.v3 v3.0.1
+)
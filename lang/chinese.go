@@ -12,18 +12,36 @@ var (
 			ArgumentMissing: "Error ArgumentMissing: 缺少文件参数。",
 			FileMissing:     "Error FileMissing: 文件 %s 不存在。",
 			ReadError:       "Error ReadError: 读取 %s 错误。",
+			ConfigError:     "Error ConfigError: 配置错误，%v",
 			EmptyMessage:    "Error EmptyMessage: 提交信息没有内容（不包括空白字符）。",
 			EmptyHeader:     "Error EmptyHeader: 标题（第一行）没有内容（不包括空白字符）。",
 			BadHeaderFormat: `Error BadHeaderFormat: 标题（第一行）不符合规范:
 	%s
 	如果您无法发现错误，请注意是否使用了中文冒号，或者冒号后面缺少空格。`,
-			WrongType:             "Error WrongType: %s, 类型关键字应为以下选项中的一个:\n%s",
-			ScopeMissing:          "Error ScopeMissing: 类型后面缺少'(scope)'。",
-			WrongScope:            "Error WrongScope: %s, 范围关键字应为以下选项中的一个:\n%s",
-			BodyMissing:           "Error BodyMissing: 消息体没有内容（不包括空白字符）。",
-			NoBlankLineBeforeBody: "Error NoBlankLineBeforeBody: 标题和消息体之间缺少空行。",
-			LineOverLong:          "Error LineOverLong: 该行长度为 %d, 超出了 %d 的限制:\n%s",
-			UndefindedError:       "Error UndefindedError: 没有预料到的错误，请提交一个错误报告。",
+			WrongType:               "Error WrongType: %s, 类型关键字应为以下选项中的一个:\n%s",
+			ScopeMissing:            "Error ScopeMissing: 类型后面缺少'(scope)'。",
+			WrongScope:              "Error WrongScope: %s, 范围应匹配以下选项中的一个:\n%s",
+			ScopeMismatch:           "Error ScopeMismatch: (%s) 未覆盖改动的路径，建议使用 (%s)",
+			WrongTypeScope:          "Error WrongTypeScope: %s, 该范围不能与类型 %s 搭配使用。",
+			SubjectLeadingSpace:     "Error SubjectLeadingSpace: 主题以空白字符开头，冒号后面只能有一个空格。",
+			WrongSubjectCase:        "Error WrongSubjectCase: 主题应为 %s 大小写形式:\n%s",
+			SubjectEndPunctuation:   "Error SubjectEndPunctuation: 主题不应以 %q 结尾:\n%s",
+			SubjectTooShort:         "Error SubjectTooShort: 主题长度为 %d, 少于 %d 的限制:\n%s",
+			SubjectTooLong:          "Error SubjectTooLong: 主题长度为 %d, 超出了 %d 的限制:\n%s",
+			NonImperativeSubject:    "Error NonImperativeSubject: %s, 主题应以祈使语气的动词开头，例如 %s:\n%s",
+			BodyMissing:             "Error BodyMissing: 消息体没有内容（不包括空白字符）。",
+			NoBlankLineBeforeBody:   "Error NoBlankLineBeforeBody: 标题和消息体之间缺少空行。",
+			MalformedTrailer:        "Error MalformedTrailer: 脚注应为 \"Token: value\" 或 \"Token #value\" 的形式:\n%s",
+			WrongTrailer:            "Error WrongTrailer: %s, 脚注关键字应为以下选项中的一个:\n%s",
+			BadTrailerValue:         "Error BadTrailerValue: 脚注 %s 的值应匹配 %s:\n%s",
+			TrailerMissing:          "Error TrailerMissing: 脚注中缺少必需的 %s。",
+			IssueRefMissing:         "Error IssueRefMissing: %s 中需要引用问题单，形式为 %s",
+			BranchTicketMissing:     "Error BranchTicketMissing: 提交信息中应引用分支 %[2]s 名称中的问题单 %[1]s。",
+			BreakingMarkerMissing:   "Error BreakingMarkerMissing: 发现 BREAKING CHANGE 脚注，标题应在冒号前用 \"!\" 标记。",
+			BreakingFooterMissing:   "Error BreakingFooterMissing: 标题用 \"!\" 标记了不兼容变更，需要用 BREAKING CHANGE 脚注加以说明。",
+			BreakingFooterForbidden: "Error BreakingFooterForbidden: 不允许使用 BREAKING CHANGE 脚注，请改为在标题的冒号前用 \"!\" 标记:\n%s",
+			LineOverLong:            "Error LineOverLong: 该行长度为 %d, 超出了 %d 的限制:\n%s",
+			UndefindedError:         "Error UndefindedError: 没有预料到的错误，请提交一个错误报告。",
 		},
 		Rule: `提交信息规范如下:
 		<type>(<scope>): <subject>
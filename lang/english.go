@@ -12,18 +12,36 @@ var (
 			ArgumentMissing: "Error ArgumentMissing: commit message file argument missing.",
 			FileMissing:     "Error FileMissing: file %s not exists.",
 			ReadError:       "Error ReadError: read file %s error.",
+			ConfigError:     "Error ConfigError: invalid config, %v",
 			EmptyMessage:    "Error EmptyMessage: commit message has no content except whitespaces.",
 			EmptyHeader:     "Error EmptyHeader: header (first line) has no content except whitespaces.",
 			BadHeaderFormat: `Error BadHeaderFormat: header (first line) not following the rule:
 	%s
 	if you can not find any error after check, maybe you use full-width colon, or lack of whitespace after the colon.`,
-			WrongType:             "Error WrongType: %s, type should be one of the keywords:\n%s",
-			ScopeMissing:          "Error ScopeMissing: (scope) is required right after type.",
-			WrongScope:            "Error WrongScope: %s, scope should be one of the keywords:\n%s",
-			BodyMissing:           "Error BodyMissing: body has no content except whitespaces.",
-			NoBlankLineBeforeBody: "Error NoBlankLineBeforeBody: no empty line between header and body.",
-			LineOverLong:          "Error LineOverLong: the length of line is %d, exceed %d:\n%s",
-			UndefindedError:       "Error UndefindedError: unexpected error occurs, please raise an issue.",
+			WrongType:               "Error WrongType: %s, type should be one of the keywords:\n%s",
+			ScopeMissing:            "Error ScopeMissing: (scope) is required right after type.",
+			WrongScope:              "Error WrongScope: %s, scope should match one of:\n%s",
+			ScopeMismatch:           "Error ScopeMismatch: (%s) does not cover the changed paths, try (%s)",
+			WrongTypeScope:          "Error WrongTypeScope: %s, scope is not allowed with type %s.",
+			SubjectLeadingSpace:     "Error SubjectLeadingSpace: subject starts with whitespace, only one space is allowed after the colon.",
+			WrongSubjectCase:        "Error WrongSubjectCase: subject should be in %s case:\n%s",
+			SubjectEndPunctuation:   "Error SubjectEndPunctuation: subject should not end with %q:\n%s",
+			SubjectTooShort:         "Error SubjectTooShort: the length of subject is %d, less than %d:\n%s",
+			SubjectTooLong:          "Error SubjectTooLong: the length of subject is %d, exceed %d:\n%s",
+			NonImperativeSubject:    "Error NonImperativeSubject: %s, subject should start with a verb in imperative mood, e.g. %s:\n%s",
+			BodyMissing:             "Error BodyMissing: body has no content except whitespaces.",
+			NoBlankLineBeforeBody:   "Error NoBlankLineBeforeBody: no empty line between header and body.",
+			MalformedTrailer:        "Error MalformedTrailer: trailer should be in the form of \"Token: value\" or \"Token #value\":\n%s",
+			WrongTrailer:            "Error WrongTrailer: %s, trailer token should be one of:\n%s",
+			BadTrailerValue:         "Error BadTrailerValue: the value of trailer %s should match %s:\n%s",
+			TrailerMissing:          "Error TrailerMissing: trailer %s is required in the footer.",
+			IssueRefMissing:         "Error IssueRefMissing: an issue reference is required in %s, in the form of %s",
+			BranchTicketMissing:     "Error BranchTicketMissing: the ticket %s in the name of branch %s should be referenced in the message.",
+			BreakingMarkerMissing:   "Error BreakingMarkerMissing: BREAKING CHANGE footer found, the header should be marked by \"!\" before the colon.",
+			BreakingFooterMissing:   "Error BreakingFooterMissing: the header is marked as breaking change by \"!\", a BREAKING CHANGE footer is required to describe it.",
+			BreakingFooterForbidden: "Error BreakingFooterForbidden: BREAKING CHANGE footer is not allowed, mark the header by \"!\" before the colon instead:\n%s",
+			LineOverLong:            "Error LineOverLong: the length of line is %d, exceed %d:\n%s",
+			UndefindedError:         "Error UndefindedError: unexpected error occurs, please raise an issue.",
 		},
 		Rule: `Commit message rule as follow:
 		<type>(<scope>): <subject>
@@ -5,16 +5,26 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-
-	"github.com/JayceChant/commit-msg/validator"
 )
 
 var (
 	versionFlag = flag.Bool("version", false, "")
+	batchFlag   = flag.Bool("z", false, "read NUL-separated messages from stdin")
+	formatFlag  = flag.String("format", defaultFormat(), "output format: "+formatNames)
 	version     string
 	goVersion   string
 	commitHash  string
 	buildTime   string
+
+	// commands are the subcommands, the arguments are taken
+	// as the commit message files if the first matches none of them
+	commands = map[string]func(args []string){
+		"config":    runConfig,
+		"init":      runInit,
+		"install":   runInstall,
+		"lint":      runLint,
+		"uninstall": runUninstall,
+	}
 )
 
 func main() {
@@ -24,12 +34,12 @@ func main() {
 		return
 	}
 
-	msgFile := ""
-	if len(os.Args) > 1 {
-		msgFile = os.Args[1]
+	if cmd, ok := commands[flag.Arg(0)]; ok {
+		cmd(flag.Args()[1:])
+		return
 	}
 
-	validator.Validate(msgFile)
+	runCheck(flag.Args(), *batchFlag, *formatFlag)
 }
 
 func printVersion(cmd string) {
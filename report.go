@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/JayceChant/commit-msg/state"
+	"github.com/JayceChant/commit-msg/validator"
+)
+
+// issue is a violation, or a failure to read the message, to report
+type issue struct {
+	State    state.State `json:"state"`
+	Severity string      `json:"severity"`
+	// Line and Column are 1-based, 0 if not bound to a line or column
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+// entry is the result of a message to report
+type entry struct {
+	// title is the heading of the message in text format
+	title string
+	// path is where the message comes from, the file, "-#N" for stdin, or the commit hash
+	path   string
+	state  state.State
+	issues []issue
+	// breaking reports whether the message tells a breaking change
+	breaking bool
+}
+
+// failed reports whether the message fails any rule
+func (e *entry) failed() bool {
+	return !e.state.IsNormal()
+}
+
+// report collects the results of several messages, prints them in format,
+// and exits with the most severe state
+type report struct {
+	format  string
+	entries []*entry
+}
+
+// formats are the output formats of a report
+var formats = map[string]func(entries []*entry) (string, error){
+	"text":       textReport,
+	"json":       jsonReport,
+	"sarif":      sarifReport,
+	"junit":      junitReport,
+	"checkstyle": checkstyleReport,
+	"github":     githubReport,
+	"gitlab":     gitlabReport,
+}
+
+// formatNames lists the output formats for the usage of -format
+const formatNames = "text, json, sarif, junit, checkstyle, github or gitlab"
+
+// defaultFormat returns the annotation format of the CI the program runs in,
+// told by the environment variables, or text if not in CI
+func defaultFormat() string {
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		return "github"
+	}
+	if os.Getenv("GITLAB_CI") != "" {
+		return "gitlab"
+	}
+	return "text"
+}
+
+// checkFormat exits with usage error if format is not supported
+func checkFormat(format string) {
+	if _, ok := formats[format]; !ok {
+		fmt.Fprintf(os.Stderr, "unknown format %q, should be %s\n", format, formatNames)
+		os.Exit(2)
+	}
+}
+
+// add records the result of the message titled title from path
+func (r *report) add(title, path string, res validator.Result) {
+	e := &entry{title: title, path: path, state: res.State, breaking: res.Breaking}
+	for _, v := range res.Violations {
+		msg := v.State.Hint(v.Args...)
+		if v.Severity == validator.SeverityWarning {
+			msg = v.State.Warning(v.Args...)
+		}
+		e.issues = append(e.issues, issue{
+			State:    v.State,
+			Severity: string(v.Severity),
+			Line:     v.Line,
+			Column:   v.Column,
+			Message:  msg,
+		})
+	}
+	r.entries = append(r.entries, e)
+}
+
+// addError records the failure s of reading the message from path
+func (r *report) addError(path string, s state.State, err error) {
+	r.entries = append(r.entries, &entry{
+		title:  path,
+		path:   path,
+		state:  s,
+		issues: []issue{{State: s, Severity: string(validator.SeverityError), Message: err.Error() + "\n" + s.Hint(path)}},
+	})
+}
+
+// exit prints the report, with the summary counting the messages as noun
+// in text format, then exits with the most severe state
+func (r *report) exit(noun string) {
+	out, err := formats[r.format](r.entries)
+	if err != nil {
+		log.Println(err)
+		state.UndefindedError.LogAndExit()
+	}
+	fmt.Print(out)
+
+	worst, failed := state.Validated, 0
+	for _, e := range r.entries {
+		if e.failed() {
+			failed++
+		}
+		if e.state.MoreSevere(worst) {
+			worst = e.state
+		}
+	}
+
+	if r.format != "text" {
+		if worst.IsNormal() {
+			os.Exit(0)
+		}
+		os.Exit(int(worst))
+	}
+
+	fmt.Printf("%d %s checked, %d failed\n", len(r.entries), noun, failed)
+	worst.Exit()
+}
+
+// textReport lists the hints of each message under its title
+func textReport(entries []*entry) (string, error) {
+	var b strings.Builder
+	for _, e := range entries {
+		fmt.Fprintln(&b, e.title)
+		if !e.failed() {
+			fmt.Fprintf(&b, "\t%s\n", e.state.Hint())
+		}
+		for _, is := range e.issues {
+			fmt.Fprintf(&b, "\t%s\n", strings.Replace(is.Message, "\n", "\n\t", -1))
+		}
+	}
+	return b.String(), nil
+}
@@ -6,6 +6,12 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
+)
+
+const (
+	errorPrefix   = "Error "
+	warningPrefix = "Warning "
 )
 
 var (
@@ -33,7 +39,8 @@ func _() {
 // State indicate the state of a commit message
 type State int8
 
-// message states
+// message states, new states are appended at the end
+// to keep the exit codes of the existing ones
 const (
 	// normal state
 	Validated State = iota
@@ -53,12 +60,104 @@ const (
 	NoBlankLineBeforeBody
 	LineOverLong
 	UndefindedError
+	// non format error
+	ConfigError
+	// format error
+	SubjectLeadingSpace
+	WrongSubjectCase
+	SubjectEndPunctuation
+	SubjectTooShort
+	SubjectTooLong
+	NonImperativeSubject
+	BreakingMarkerMissing
+	BreakingFooterMissing
+	BreakingFooterForbidden
+	MalformedTrailer
+	WrongTrailer
+	BadTrailerValue
+	TrailerMissing
+	IssueRefMissing
+	BranchTicketMissing
+	ScopeMismatch
+	WrongTypeScope
 )
 
+// stateCount is the number of states, update it on appending a state
+const stateCount = int(WrongTypeScope) + 1
+
+// severityOrder lists the errors from the most severe to the least.
+// The earlier one breaks the structure that the later ones rely on.
+var severityOrder = []State{
+	ArgumentMissing,
+	FileMissing,
+	ReadError,
+	ConfigError,
+	EmptyMessage,
+	EmptyHeader,
+	BadHeaderFormat,
+	WrongType,
+	ScopeMissing,
+	WrongScope,
+	ScopeMismatch,
+	WrongTypeScope,
+	SubjectLeadingSpace,
+	WrongSubjectCase,
+	SubjectEndPunctuation,
+	SubjectTooShort,
+	SubjectTooLong,
+	NonImperativeSubject,
+	BodyMissing,
+	NoBlankLineBeforeBody,
+	MalformedTrailer,
+	WrongTrailer,
+	BadTrailerValue,
+	TrailerMissing,
+	IssueRefMissing,
+	BranchTicketMissing,
+	BreakingMarkerMissing,
+	BreakingFooterMissing,
+	BreakingFooterForbidden,
+	LineOverLong,
+	UndefindedError,
+}
+
+// precedence is the index of the errors in severityOrder
+var precedence = func() map[State]int {
+	m := make(map[State]int, len(severityOrder))
+	for i, s := range severityOrder {
+		m[s] = i
+	}
+	return m
+}()
+
 // LogAndExit ...
 func (state State) LogAndExit(v ...interface{}) {
-	log.Println(lang.GetHint(state, v...))
+	state.Log(v...)
+	state.Exit()
+}
 
+// Log prints the hint of the state
+func (state State) Log(v ...interface{}) {
+	log.Println(state.Hint(v...))
+}
+
+// Hint returns the hint of the state in the language initialized
+func (state State) Hint(v ...interface{}) string {
+	return lang.GetHint(state, v...)
+}
+
+// Warning returns the hint of the state as a warning, for a rule not failing the message
+func (state State) Warning(v ...interface{}) string {
+	hint := state.Hint(v...)
+	if strings.HasPrefix(hint, errorPrefix) {
+		return warningPrefix + hint[len(errorPrefix):]
+	}
+	return hint
+}
+
+// Exit prints the rule if the state is a format error,
+// then exits with the state as exit code.
+func (state State) Exit() {
 	if state.IsNormal() {
 		os.Exit(0)
 	}
@@ -77,7 +176,17 @@ func (state State) IsNormal() bool {
 
 // IsFormatError return if the state a format error
 func (state State) IsFormatError() bool {
-	return state >= EmptyMessage
+	return state >= EmptyMessage && state != ConfigError
+}
+
+// MoreSevere return if the state is more severe than other.
+// Errors are more severe than normal states. Among errors,
+// the earlier one in severityOrder is the more severe.
+func (state State) MoreSevere(other State) bool {
+	if state.IsNormal() || other.IsNormal() {
+		return !state.IsNormal() && other.IsNormal()
+	}
+	return precedence[state] < precedence[other]
 }
 
 func (state State) MarshalText() (text []byte, err error) {
@@ -86,7 +195,7 @@ func (state State) MarshalText() (text []byte, err error) {
 
 func (state *State) UnmarshalText(text []byte) error {
 	str := string(text)
-	for s := Validated; s <= UndefindedError; s++ {
+	for s := Validated; int(s) < stateCount; s++ {
 		if s.String() == str {
 			*state = s
 			return nil
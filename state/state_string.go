@@ -23,15 +23,34 @@ func _() {
 	_ = x[NoBlankLineBeforeBody-12]
 	_ = x[LineOverLong-13]
 	_ = x[UndefindedError-14]
+	_ = x[ConfigError-15]
+	_ = x[SubjectLeadingSpace-16]
+	_ = x[WrongSubjectCase-17]
+	_ = x[SubjectEndPunctuation-18]
+	_ = x[SubjectTooShort-19]
+	_ = x[SubjectTooLong-20]
+	_ = x[NonImperativeSubject-21]
+	_ = x[BreakingMarkerMissing-22]
+	_ = x[BreakingFooterMissing-23]
+	_ = x[BreakingFooterForbidden-24]
+	_ = x[MalformedTrailer-25]
+	_ = x[WrongTrailer-26]
+	_ = x[BadTrailerValue-27]
+	_ = x[TrailerMissing-28]
+	_ = x[IssueRefMissing-29]
+	_ = x[BranchTicketMissing-30]
+	_ = x[ScopeMismatch-31]
+	_ = x[WrongTypeScope-32]
 }
 
-const _State_name = "ValidatedMergeArgumentMissingFileMissingReadErrorEmptyMessageEmptyHeaderBadHeaderFormatWrongTypeScopeMissingWrongScopeBodyMissingNoBlankLineBeforeBodyLineOverLongUndefindedError"
+const _State_name = "ValidatedMergeArgumentMissingFileMissingReadErrorEmptyMessageEmptyHeaderBadHeaderFormatWrongTypeScopeMissingWrongScopeBodyMissingNoBlankLineBeforeBodyLineOverLongUndefindedErrorConfigErrorSubjectLeadingSpaceWrongSubjectCaseSubjectEndPunctuationSubjectTooShortSubjectTooLongNonImperativeSubjectBreakingMarkerMissingBreakingFooterMissingBreakingFooterForbiddenMalformedTrailerWrongTrailerBadTrailerValueTrailerMissingIssueRefMissingBranchTicketMissingScopeMismatchWrongTypeScope"
 
-var _State_index = [...]uint8{0, 9, 14, 29, 40, 49, 61, 72, 87, 96, 108, 118, 129, 150, 162, 177}
+var _State_index = [...]uint16{0, 9, 14, 29, 40, 49, 61, 72, 87, 96, 108, 118, 129, 150, 162, 177, 188, 207, 223, 244, 259, 273, 293, 314, 335, 358, 374, 386, 401, 415, 430, 449, 462, 476}
 
 func (i State) String() string {
-	if i < 0 || i >= State(len(_State_index)-1) {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_State_index)-1 {
 		return "State(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
-	return _State_name[_State_index[i]:_State_index[i+1]]
+	return _State_name[_State_index[idx]:_State_index[idx+1]]
 }
@@ -0,0 +1,50 @@
+package state
+
+import "testing"
+
+func TestExitCodes(t *testing.T) {
+	// exit codes are relied on by scripts, never renumber the existing states
+	var codeCases = []struct {
+		state State
+		code  int
+	}{
+		{Validated, 0},
+		{ReadError, 4},
+		{EmptyMessage, 5},
+		{BadHeaderFormat, 7},
+		{WrongType, 8},
+		{LineOverLong, 13},
+		{UndefindedError, 14},
+		{ConfigError, 15},
+	}
+	for _, tt := range codeCases {
+		if int(tt.state) != tt.code {
+			t.Errorf("%v got code %d, want %d", tt.state, int(tt.state), tt.code)
+		}
+	}
+
+	for s := ArgumentMissing; int(s) < stateCount; s++ {
+		if _, ok := precedence[s]; !ok {
+			t.Errorf("%v is missing in severityOrder", s)
+		}
+	}
+}
+
+func TestMoreSevere(t *testing.T) {
+	var severeCases = []struct {
+		state, other State
+		want         bool
+	}{
+		{ConfigError, EmptyMessage, true},
+		{BadHeaderFormat, SubjectTooLong, true},
+		{WrongTypeScope, BodyMissing, true},
+		{LineOverLong, BreakingFooterMissing, false},
+		{LineOverLong, Validated, true},
+		{Merge, LineOverLong, false},
+	}
+	for _, tt := range severeCases {
+		if got := tt.state.MoreSevere(tt.other); got != tt.want {
+			t.Errorf("%v.MoreSevere(%v) got %v, want %v", tt.state, tt.other, got, tt.want)
+		}
+	}
+}
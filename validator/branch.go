@@ -0,0 +1,100 @@
+package validator
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+
+	"github.com/JayceChant/commit-msg/dir"
+	"github.com/JayceChant/commit-msg/state"
+)
+
+// ForBranch returns a new config with the overrides in Branches, whose patterns match branch,
+// merged on top of cfg in the order of patterns, see Merge. The ticket required by
+// BranchTicketPattern is also taken from branch. cfg is not modified.
+func (cfg *Config) ForBranch(branch string) *Config {
+	patterns := make([]string, 0, len(cfg.Branches))
+	for p := range cfg.Branches {
+		patterns = append(patterns, p)
+	}
+	sort.Strings(patterns)
+
+	merged := cfg.Merge(&Config{})
+	for _, p := range patterns {
+		// a null override, e.g. release/*: with no value, changes nothing
+		if ok, _ := path.Match(p, branch); ok && branch != "" && cfg.Branches[p] != nil {
+			merged = merged.Merge(cfg.Branches[p])
+		}
+	}
+	merged.branch = branch
+	return merged
+}
+
+// LoadCurrentConfig loads the config files of the current repository, see ConfigFiles,
+// with the overrides of the branch checked out applied, see ForBranch.
+func LoadCurrentConfig() (*Config, error) {
+	cfg, err := LoadConfig(ConfigFiles()...)
+	if err != nil {
+		return cfg, err
+	}
+	return cfg.ForBranch(dir.Branch()), nil
+}
+
+// branchTicket returns the ticket key in the branch by BranchTicketPattern, "" if none
+func (cfg *Config) branchTicket() string {
+	if cfg.BranchTicketPattern == "" || cfg.branch == "" {
+		return ""
+	}
+	re, err := regexp.Compile(cfg.BranchTicketPattern)
+	if err != nil {
+		return ""
+	}
+	return re.FindString(cfg.branch)
+}
+
+// validateBranchTicket checks that msg references the ticket key in the branch
+func validateBranchTicket(msg string, config *Config) []Violation {
+	ticket := config.branchTicket()
+	if ticket == "" {
+		return nil
+	}
+
+	re := regexp.MustCompile(`(^|\W)` + regexp.QuoteMeta(ticket) + `(\W|$)`)
+	if re.MatchString(msg) {
+		return nil
+	}
+	return []Violation{violation(state.BranchTicketMissing, 0, 0, "", ticket, config.branch)}
+}
+
+// branchProblems checks the branch patterns and the overrides of them
+func (cfg *Config) branchProblems() []problem {
+	var ps []problem
+	if _, err := regexp.Compile(cfg.BranchTicketPattern); err != nil {
+		ps = append(ps, problem{"branchTicketPattern", fmt.Sprintf("branchTicketPattern: %v", err)})
+	}
+
+	patterns := make([]string, 0, len(cfg.Branches))
+	for p := range cfg.Branches {
+		patterns = append(patterns, p)
+	}
+	sort.Strings(patterns)
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			ps = append(ps, problem{"branches", fmt.Sprintf("branch pattern %q: %v", p, err)})
+			continue
+		}
+
+		override := cfg.Branches[p]
+		if override == nil {
+			continue
+		}
+		if len(override.Branches) > 0 {
+			ps = append(ps, problem{"branches", fmt.Sprintf("branches of %q: branches can not be nested", p)})
+		}
+		for _, sub := range append(override.problems(), override.overlapProblems()...) {
+			ps = append(ps, problem{"branches", fmt.Sprintf("branches of %q: %s", p, sub.msg)})
+		}
+	}
+	return ps
+}
@@ -1,9 +1,12 @@
 package validator
 
 import (
-	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
+	"io/ioutil"
 	"os"
+	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/JayceChant/commit-msg/dir"
@@ -11,27 +14,96 @@ import (
 	"github.com/JayceChant/commit-msg/state"
 )
 
+var (
+	// configFileNames are the names of config file in each place, in the order of loading
+	configFileNames = []string{ConfigName + ".json", ConfigName + ".yaml", ConfigName + ".yml", ConfigName + ".toml"}
+)
+
 const (
-	configFileName = ".commit-msg.json"
+	// ConfigName is the name of config files without extension,
+	// which tells the format of the file
+	ConfigName = ".commit-msg"
+	// inherit is the list element standing for the list of the upper layer,
+	// e.g. ["...", "wip"] appends "wip" to the inherited list.
+	inherit = "..."
 )
 
-type validateConfig struct {
-	Lang          string   `json:"lang,omitempty"`
-	BodyRequired  bool     `json:"bodyRequired,omitempty"`
-	LineLimit     int      `json:"lineLimit,omitempty"`
-	Types         []string `json:"types,omitempty"`
-	DenyTypes     []string `json:"denyTypes,omitempty"`
-	ScopeRequired bool     `json:"scopeRequired,omitempty"`
-	Scopes        []string `json:"scopes,omitempty"`
+// Config holds the rules a commit message is checked against.
+type Config struct {
+	Lang          string   `json:"lang,omitempty" yaml:"lang,omitempty" toml:"lang,omitempty"`
+	BodyRequired  bool     `json:"bodyRequired,omitempty" yaml:"bodyRequired,omitempty" toml:"bodyRequired,omitempty"`
+	LineLimit     int      `json:"lineLimit,omitempty" yaml:"lineLimit,omitempty" toml:"lineLimit,omitempty"`
+	Types         []string `json:"types,omitempty" yaml:"types,omitempty" toml:"types,omitempty"`
+	DenyTypes     []string `json:"denyTypes,omitempty" yaml:"denyTypes,omitempty" toml:"denyTypes,omitempty"`
+	ScopeRequired bool     `json:"scopeRequired,omitempty" yaml:"scopeRequired,omitempty" toml:"scopeRequired,omitempty"`
+	Scopes        []string `json:"scopes,omitempty" yaml:"scopes,omitempty" toml:"scopes,omitempty"`
+	// MultipleScopes allows several scopes in a header separated by ScopeDelimiter, e.g. feat(api,ui),
+	// each of them is checked against Scopes
+	MultipleScopes bool `json:"multipleScopes,omitempty" yaml:"multipleScopes,omitempty" toml:"multipleScopes,omitempty"`
+	// ScopeDelimiter separates the scopes if MultipleScopes is true, "," if empty
+	ScopeDelimiter string `json:"scopeDelimiter,omitempty" yaml:"scopeDelimiter,omitempty" toml:"scopeDelimiter,omitempty"`
+	// ScopePaths maps the path patterns, e.g. services/billing/**, to the scopes the changes
+	// under them belong to, the scope of the header must cover those of the changed files
+	ScopePaths map[string]string `json:"scopePaths,omitempty" yaml:"scopePaths,omitempty" toml:"scopePaths,omitempty"`
+	// DenySubjectLeadingSpace rejects the subject starting with whitespace, e.g. "feat:  add x"
+	DenySubjectLeadingSpace bool `json:"denySubjectLeadingSpace,omitempty" yaml:"denySubjectLeadingSpace,omitempty" toml:"denySubjectLeadingSpace,omitempty"`
+	// SubjectCase is the case required of the subject, lower, upper, sentence or any
+	SubjectCase string `json:"subjectCase,omitempty" yaml:"subjectCase,omitempty" toml:"subjectCase,omitempty"`
+	// DenySubjectEnd are the punctuations the subject must not end with
+	DenySubjectEnd []string `json:"denySubjectEnd,omitempty" yaml:"denySubjectEnd,omitempty" toml:"denySubjectEnd,omitempty"`
+	// SubjectMinLength and SubjectMaxLength limit the length of the subject in characters, 0 for no limit
+	SubjectMinLength int `json:"subjectMinLength,omitempty" yaml:"subjectMinLength,omitempty" toml:"subjectMinLength,omitempty"`
+	SubjectMaxLength int `json:"subjectMaxLength,omitempty" yaml:"subjectMaxLength,omitempty" toml:"subjectMaxLength,omitempty"`
+	// SubjectImperative requires the subject to start with a verb in imperative mood, English only
+	SubjectImperative bool `json:"subjectImperative,omitempty" yaml:"subjectImperative,omitempty" toml:"subjectImperative,omitempty"`
+	// BreakingFooter tells whether a BREAKING CHANGE footer is required along with the "!" marker
+	// of the header, required, forbidden or any
+	BreakingFooter string `json:"breakingFooter,omitempty" yaml:"breakingFooter,omitempty" toml:"breakingFooter,omitempty"`
+	// Trailers are the trailer tokens allowed in the footer, any token is allowed if empty
+	Trailers []string `json:"trailers,omitempty" yaml:"trailers,omitempty" toml:"trailers,omitempty"`
+	// RequiredTrailers are the trailer tokens required in the footer
+	RequiredTrailers []string `json:"requiredTrailers,omitempty" yaml:"requiredTrailers,omitempty" toml:"requiredTrailers,omitempty"`
+	// TrailerPatterns maps the trailer tokens to the regular expressions their values must match
+	TrailerPatterns map[string]string `json:"trailerPatterns,omitempty" yaml:"trailerPatterns,omitempty" toml:"trailerPatterns,omitempty"`
+	// IssuePattern is the regular expression of the issue reference required, e.g. [A-Z]+-\d+ or #\d+,
+	// no reference is required if empty
+	IssuePattern string `json:"issuePattern,omitempty" yaml:"issuePattern,omitempty" toml:"issuePattern,omitempty"`
+	// IssueLocations are where the issue reference may appear, subject, body or trailers, all if empty
+	IssueLocations []string `json:"issueLocations,omitempty" yaml:"issueLocations,omitempty" toml:"issueLocations,omitempty"`
+	// IssueTrailers are the trailer tokens where the issue reference may appear, any if empty
+	IssueTrailers []string `json:"issueTrailers,omitempty" yaml:"issueTrailers,omitempty" toml:"issueTrailers,omitempty"`
+	// IssuePrefixes are the project prefixes allowed of the issue reference, any if empty
+	IssuePrefixes []string `json:"issuePrefixes,omitempty" yaml:"issuePrefixes,omitempty" toml:"issuePrefixes,omitempty"`
+	// BranchTicketPattern is the regular expression of the ticket key in the branch name,
+	// e.g. [A-Z]+-\d+, which must be referenced in the message if found
+	BranchTicketPattern string `json:"branchTicketPattern,omitempty" yaml:"branchTicketPattern,omitempty" toml:"branchTicketPattern,omitempty"`
+	// TypeRules maps the types to the rules of scope and body for them, "*" for the types not listed
+	TypeRules map[string]*TypeRule `json:"typeRules,omitempty" yaml:"typeRules,omitempty" toml:"typeRules,omitempty"`
+	// Branches maps the branch patterns, e.g. release/*, to the config overriding this one
+	// on the branches matching, see ForBranch
+	Branches map[string]*Config `json:"branches,omitempty" yaml:"branches,omitempty" toml:"branches,omitempty"`
+	// Severity maps the rules, named by their states, to their severities
+	Severity map[string]Severity `json:"severity,omitempty" yaml:"severity,omitempty" toml:"severity,omitempty"`
+
+	// sources records the keys set explicitly, to tell an explicit false or 0
+	// from an unset one, along with the config file each key comes from,
+	// "" if set programmatically.
+	sources map[string]string
+	// branch is the branch checked out, set by ForBranch
+	branch string
+	// files are the paths of the files changed by the commit, set by ForFiles
+	files []string
 }
 
 // use type alias to avoid new type and unexpected method definition
 type dummy = struct{}
 
 var (
-	// globalConfig ...
-	globalConfig *validateConfig = &validateConfig{Lang: "en", BodyRequired: false, LineLimit: 80}
-	// TypeSet ...
+	// globalConfig is the config loaded from the config files, used by Validate
+	globalConfig = NewConfig()
+	// configErr is the error loading globalConfig, Validate fails with it
+	configErr error
+	// TypeSet is the built-in type keywords, before Types and DenyTypes applied
 	TypeSet = map[string]dummy{
 		"feat":     {}, // new feature 新功能
 		"fix":      {}, // fix bug 修复
@@ -45,45 +117,381 @@ var (
 		"ci":       {}, // continuous integration 持续集成相关
 		"docker":   {}, // 容器相关
 	}
-	// TypesStr ...
+	// TypesStr lists the type keywords allowed by the global config
 	TypesStr string
 )
 
-func loadConfig(path string, cfg *validateConfig) *validateConfig {
-	f, err := os.Open(path)
-	if err != nil && !os.IsExist(err) {
-		return cfg
+// typeSet returns the type keywords allowed by the config,
+// the built-in TypeSet with Types added and DenyTypes removed.
+func (cfg *Config) typeSet() map[string]dummy {
+	set := make(map[string]dummy, len(TypeSet)+len(cfg.Types))
+	for t := range TypeSet {
+		set[t] = dummy{}
+	}
+
+	for _, t := range cfg.Types {
+		set[t] = dummy{}
 	}
-	defer f.Close()
 
-	dec := json.NewDecoder(f)
-	if err := dec.Decode(cfg); err != nil {
-		log.Println(err)
+	for _, t := range cfg.DenyTypes {
+		delete(set, t)
 	}
-	return cfg
+	return set
 }
 
-func init() {
-	paths := dir.FindFiles(configFileName)
+// allowedTypes sorts the keywords in set, followed by the revert keywords
+func allowedTypes(set map[string]dummy) []string {
+	types := make([]string, 0, len(set)+2)
+	for t := range set {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return append(types, "revert", "Revert")
+}
+
+// typesStr joins the allowed type keywords for hints
+func typesStr(set map[string]dummy) string {
+	return strings.Join(allowedTypes(set), ", ")
+}
+
+// NewConfig returns a config with the default rules
+func NewConfig() *Config {
+	return &Config{Lang: "en", BodyRequired: false, LineLimit: 80}
+}
+
+// LoadConfig loads the config files in order on top of the default config,
+// so the later file takes precedence over the former, see Merge.
+// Paths not existing are skipped. If a file fails to decode,
+// the config merged so far is returned along with the error.
+func LoadConfig(paths ...string) (*Config, error) {
+	cfg := NewConfig()
 	for _, p := range paths {
-		// TODO: fix json value overlaping
-		globalConfig = loadConfig(p, globalConfig)
+		c, err := loadConfig(p)
+		if err != nil {
+			return cfg, err
+		}
+
+		if c != nil {
+			cfg = cfg.Merge(c)
+		}
 	}
+	return cfg, nil
+}
 
-	for _, t := range globalConfig.Types {
-		TypeSet[t] = dummy{}
+// loadConfig decodes a single config file in the format told by its extension,
+// returns nil if the file not exists. Read errors, unknown keys, mismatched types
+// and invalid values are all reported as *ConfigError.
+func loadConfig(path string) (*Config, error) {
+	buf, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		var pe *os.PathError
+		if errors.As(err, &pe) {
+			err = pe.Err
+		}
+		return nil, &ConfigError{Path: path, Msg: err.Error()}
 	}
 
-	for _, t := range globalConfig.DenyTypes {
-		delete(TypeSet, t)
+	cfg, err := decodeConfig(buf, formatOf(path))
+	if err != nil {
+		err.(*ConfigError).Path = path
+		return nil, err
 	}
 
-	types := make([]string, 0, len(TypeSet)+2)
-	for t := range TypeSet {
-		types = append(types, t)
+	cfg.setSource(path)
+	return cfg, nil
+}
+
+// setSource sets the source of the keys set explicitly, including those of the overrides of branches
+func (cfg *Config) setSource(path string) {
+	for k := range cfg.sources {
+		cfg.sources[k] = path
+	}
+	for _, override := range cfg.Branches {
+		if override != nil {
+			override.setSource(path)
+		}
+	}
+}
+
+func decodeConfig(buf []byte, f format) (*Config, error) {
+	keys, err := f.keys(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	known := configKeys()
+	positions := make(map[string]key, len(keys))
+	for _, k := range keys {
+		if !known[k.name] {
+			return nil, &ConfigError{Line: k.line, Column: k.column, Msg: fmt.Sprintf("unknown key %q", k.name)}
+		}
+		positions[k.name] = k
+	}
+
+	cfg := &Config{}
+	if err := f.decode(buf, cfg); err != nil {
+		return nil, err
+	}
+
+	if ps := append(cfg.problems(), cfg.overlapProblems()...); len(ps) > 0 {
+		k := positions[ps[0].key]
+		return nil, &ConfigError{Line: k.line, Column: k.column, Msg: ps[0].msg}
+	}
+
+	cfg.sources = make(map[string]string, len(keys))
+	for _, k := range keys {
+		cfg.sources[k.name] = ""
+	}
+
+	if len(cfg.Branches) > 0 {
+		// keys set in the overrides of branches, which are not listed by f.keys
+		var m map[string]interface{}
+		if err := f.decodeMap(buf, &m); err != nil {
+			return nil, err
+		}
+		branches, _ := m["branches"].(map[string]interface{})
+		for p, override := range cfg.Branches {
+			if override == nil {
+				continue
+			}
+			set, _ := branches[p].(map[string]interface{})
+			override.sources = make(map[string]string, len(set))
+			for k := range set {
+				override.sources[k] = ""
+			}
+		}
+	}
+	return cfg, nil
+}
+
+// configKeys returns the keys of all the config items
+func configKeys() map[string]bool {
+	t := reflect.TypeOf(Config{})
+	keys := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if k := fieldKey(t.Field(i)); k != "" {
+			keys[k] = true
+		}
+	}
+	return keys
+}
+
+// problem is an invalid value of the config item key
+type problem struct {
+	key string
+	msg string
+}
+
+// problems checks the values in the config, returns the invalid ones
+func (cfg *Config) problems() []problem {
+	var ps []problem
+	if cfg.LineLimit < 0 {
+		ps = append(ps, problem{"lineLimit", fmt.Sprintf("lineLimit %d is negative, set 0 to skip line length checking", cfg.LineLimit)})
+	}
+
+	switch cfg.SubjectCase {
+	case "", caseAny, caseLower, caseUpper, caseSentence:
+	default:
+		ps = append(ps, problem{"subjectCase", fmt.Sprintf("subjectCase %q should be lower, upper, sentence or any", cfg.SubjectCase)})
+	}
+
+	switch cfg.BreakingFooter {
+	case "", breakingAny, breakingRequired, breakingForbidden:
+	default:
+		ps = append(ps, problem{"breakingFooter", fmt.Sprintf("breakingFooter %q should be required, forbidden or any", cfg.BreakingFooter)})
+	}
+
+	if cfg.SubjectMinLength < 0 {
+		ps = append(ps, problem{"subjectMinLength", fmt.Sprintf("subjectMinLength %d is negative, set 0 for no limit", cfg.SubjectMinLength)})
+	}
+	if cfg.SubjectMaxLength < 0 {
+		ps = append(ps, problem{"subjectMaxLength", fmt.Sprintf("subjectMaxLength %d is negative, set 0 for no limit", cfg.SubjectMaxLength)})
+	} else if cfg.SubjectMaxLength > 0 && cfg.SubjectMaxLength < cfg.SubjectMinLength {
+		ps = append(ps, problem{"subjectMaxLength", fmt.Sprintf("subjectMaxLength %d is less than subjectMinLength %d", cfg.SubjectMaxLength, cfg.SubjectMinLength)})
+	}
+
+	lists := []struct {
+		key  string
+		list []string
+	}{
+		{"types", cfg.Types},
+		{"denyTypes", cfg.DenyTypes},
+		{"scopes", cfg.Scopes},
+		{"denySubjectEnd", cfg.DenySubjectEnd},
+		{"trailers", cfg.Trailers},
+		{"requiredTrailers", cfg.RequiredTrailers},
+		{"issueTrailers", cfg.IssueTrailers},
+		{"issuePrefixes", cfg.IssuePrefixes},
+	}
+	for _, l := range lists {
+		for _, s := range l.list {
+			if isEmpty(s) {
+				ps = append(ps, problem{l.key, fmt.Sprintf("%s contains empty string", l.key)})
+				break
+			}
+		}
+	}
+
+	ps = append(ps, cfg.scopeProblems()...)
+	ps = append(ps, cfg.typeRuleProblems()...)
+	ps = append(ps, cfg.trailerProblems()...)
+	ps = append(ps, cfg.issueProblems()...)
+	ps = append(ps, cfg.branchProblems()...)
+	return append(ps, cfg.severityProblems()...)
+}
+
+// overlapProblems checks the keywords in both Types and DenyTypes, which is only a problem
+// within a single file, as DenyTypes of a later file removes those added by an earlier one.
+func (cfg *Config) overlapProblems() []problem {
+	var ps []problem
+	deny := make(map[string]bool, len(cfg.DenyTypes))
+	for _, t := range cfg.DenyTypes {
+		deny[t] = true
+	}
+	for _, t := range cfg.Types {
+		if deny[t] && t != inherit {
+			ps = append(ps, problem{"denyTypes", fmt.Sprintf("type %q is in both types and denyTypes", t)})
+		}
+	}
+	return ps
+}
+
+// Validate checks the values in the config, returns the first invalid one as error
+func (cfg *Config) Validate() error {
+	if ps := cfg.problems(); len(ps) > 0 {
+		return errors.New(ps[0].msg)
+	}
+	return nil
+}
+
+// Merge returns a new config with other merged on top of cfg, neither is modified.
+// Fields set in other take precedence over those of cfg. A field is set if its key
+// is present in the config file other loaded from, even if false or 0,
+// or if the field is non-zero when other is constructed programmatically.
+// A list set in other replaces the one of cfg, unless it contains the element "...",
+// which is replaced by the list of cfg, so ["...", "wip"] appends to it.
+// A map set in other is merged into the one of cfg entry by entry.
+func (cfg *Config) Merge(other *Config) *Config {
+	merged := *cfg
+	merged.sources = make(map[string]string, len(cfg.sources)+len(other.sources))
+	for k, src := range cfg.sources {
+		merged.sources[k] = src
+	}
+
+	mv := reflect.ValueOf(&merged).Elem()
+	ov := reflect.ValueOf(other).Elem()
+	for i := 0; i < mv.NumField(); i++ {
+		key := fieldKey(mv.Type().Field(i))
+		if key == "" || !other.isSet(key, ov.Field(i)) {
+			continue
+		}
+
+		src := other.sources[key]
+		if list, ok := ov.Field(i).Interface().([]string); ok {
+			mv.Field(i).Set(reflect.ValueOf(mergeList(mv.Field(i).Interface().([]string), list)))
+			if upper := cfg.sources[key]; upper != "" && containsInherit(list) {
+				src = upper + ", " + src
+			}
+		} else if ov.Field(i).Kind() == reflect.Map {
+			mv.Field(i).Set(mergeMap(mv.Field(i), ov.Field(i)))
+			if upper := cfg.sources[key]; upper != "" && mv.Field(i).Len() > ov.Field(i).Len() {
+				src = upper + ", " + src
+			}
+		} else {
+			mv.Field(i).Set(ov.Field(i))
+		}
+		merged.sources[key] = src
+	}
+	return &merged
+}
+
+func (cfg *Config) isSet(key string, field reflect.Value) bool {
+	_, explicit := cfg.sources[key]
+	return explicit || !field.IsZero()
+}
+
+// Item is a config item with its effective value
+type Item struct {
+	Key   string
+	Value interface{}
+	// Source is the config files the value comes from, "" for the default
+	// or the value set programmatically
+	Source string
+}
+
+// Items lists all the config items, in the order of Config fields
+func (cfg *Config) Items() []Item {
+	v := reflect.ValueOf(cfg).Elem()
+	items := make([]Item, 0, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		if key := fieldKey(v.Type().Field(i)); key != "" {
+			items = append(items, Item{Key: key, Value: v.Field(i).Interface(), Source: cfg.sources[key]})
+		}
+	}
+	return items
+}
+
+// AllowedTypes lists the type keywords allowed by the config, see TypeSet
+func (cfg *Config) AllowedTypes() []string {
+	return allowedTypes(cfg.typeSet())
+}
+
+// fieldKey returns the config key of an exported field, or "" for unexported ones
+func fieldKey(f reflect.StructField) string {
+	if f.PkgPath != "" {
+		return ""
+	}
+	return strings.Split(f.Tag.Get("json"), ",")[0]
+}
+
+func containsInherit(list []string) bool {
+	for _, s := range list {
+		if s == inherit {
+			return true
+		}
 	}
-	types = append(types, "revert", "Revert")
-	TypesStr = strings.Join(types, ", ")
+	return false
+}
+
+// mergeList returns a new list of other with the inherit element replaced by upper
+func mergeList(upper, other []string) []string {
+	var merged []string
+	for _, s := range other {
+		if s == inherit {
+			merged = append(merged, upper...)
+		} else {
+			merged = append(merged, s)
+		}
+	}
+	return merged
+}
+
+// mergeMap returns a new map of upper with the entries of other set on it
+func mergeMap(upper, other reflect.Value) reflect.Value {
+	merged := reflect.MakeMapWithSize(other.Type(), upper.Len()+other.Len())
+	for _, m := range []reflect.Value{upper, other} {
+		iter := m.MapRange()
+		for iter.Next() {
+			merged.SetMapIndex(iter.Key(), iter.Value())
+		}
+	}
+	return merged
+}
+
+// ConfigFiles returns the paths where config files may be placed,
+// in the order of loading, see dir.FindFiles
+func ConfigFiles() []string {
+	return dir.FindFiles(configFileNames...)
+}
+
+func init() {
+	cfg, err := LoadCurrentConfig()
+	globalConfig, configErr = cfg, err
+
+	TypesStr = typesStr(globalConfig.typeSet())
 
 	state.Init(lang.LoadLanguage(globalConfig.Lang), TypesStr)
 }
@@ -0,0 +1,334 @@
+package validator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JayceChant/commit-msg/state"
+)
+
+func TestMerge(t *testing.T) {
+	base := &Config{Lang: "en", LineLimit: 80, BodyRequired: true, Types: []string{"deps"}, Scopes: []string{"model"}}
+	other := &Config{
+		LineLimit: 0,
+		Types:     []string{"wip"},
+		Scopes:    []string{"...", "view"},
+		sources:   map[string]string{"lineLimit": "b", "types": "b", "scopes": "b"},
+	}
+
+	got := base.Merge(other)
+	got.sources = nil
+	want := &Config{Lang: "en", LineLimit: 0, BodyRequired: true, Types: []string{"wip"}, Scopes: []string{"model", "view"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge got %+v, want %+v", got, want)
+	}
+
+	if base.LineLimit != 80 || len(base.Scopes) != 1 {
+		t.Errorf("Merge modified the receiver: %+v", base)
+	}
+
+	got = base.Merge(&Config{BodyRequired: false, LineLimit: 72})
+	if !got.BodyRequired || got.LineLimit != 72 {
+		t.Errorf("Merge of programmatic config, zero field should be unset: %+v", got)
+	}
+}
+
+func TestMergeSeverity(t *testing.T) {
+	base := &Config{Severity: map[string]Severity{"LineOverLong": SeverityWarning, "ScopeMissing": SeverityOff}}
+	other := &Config{Severity: map[string]Severity{"ScopeMissing": SeverityError}}
+
+	got := base.Merge(other).Severity
+	want := map[string]Severity{"LineOverLong": SeverityWarning, "ScopeMissing": SeverityError}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge got severity %v, want %v", got, want)
+	}
+	if base.Severity["ScopeMissing"] != SeverityOff {
+		t.Errorf("Merge modified the receiver: %v", base.Severity)
+	}
+}
+
+func TestMergeList(t *testing.T) {
+	var listCases = []struct {
+		upper []string
+		other []string
+		want  []string
+	}{
+		{[]string{"a"}, []string{"b"}, []string{"b"}},
+		{[]string{"a"}, []string{"...", "b"}, []string{"a", "b"}},
+		{[]string{"a"}, []string{"b", "..."}, []string{"b", "a"}},
+		{nil, []string{"...", "b"}, []string{"b"}},
+		{[]string{"a"}, []string{}, nil},
+	}
+	for _, tt := range listCases {
+		if got := mergeList(tt.upper, tt.other); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("mergeList(%v, %v) got %v, want %v", tt.upper, tt.other, got, tt.want)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	got, err := LoadConfig("testcase/global_config.json", "file_not_existed.json", "testcase/project_config.json")
+	if err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+
+	var sourceCases = []struct {
+		key    string
+		source string
+	}{
+		{"lang", "testcase/global_config.json"},
+		{"bodyRequired", "testcase/project_config.json"},
+		{"scopeRequired", ""},
+		{"scopes", "testcase/global_config.json, testcase/project_config.json"},
+	}
+	items := make(map[string]Item)
+	for _, item := range got.Items() {
+		items[item.Key] = item
+	}
+	for _, tt := range sourceCases {
+		if src := items[tt.key].Source; src != tt.source {
+			t.Errorf("source of %s got %q, want %q", tt.key, src, tt.source)
+		}
+	}
+
+	got.sources = nil
+	want := &Config{
+		Lang:         "zh",
+		BodyRequired: false,
+		LineLimit:    72,
+		Types:        []string{"deps"},
+		DenyTypes:    []string{"docker"},
+		Scopes:       []string{"model", "view", "controller"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadConfig got %+v, want %+v", got, want)
+	}
+
+	if _, err := LoadConfig("testcase/normal_sample.txt"); err == nil {
+		t.Errorf("LoadConfig of a non-json file, expect error")
+	}
+}
+
+func TestLoadConfigDenyTypes(t *testing.T) {
+	// denyTypes of the project removes the type added by the global config
+	cfg, err := LoadConfig("testcase/global_wip.json", "testcase/project_deny_wip.yaml")
+	if err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+	if res, err := Check("wip: some changes\n\nbody", *cfg); err != nil || res.State != state.WrongType {
+		t.Errorf("Check got %v, %v, want WrongType", res.State, err)
+	}
+	if res, err := Check("feat: some changes\n\nbody", *cfg); err != nil || res.State != state.Validated {
+		t.Errorf("Check got %v, %v, want Validated", res.State, err)
+	}
+}
+
+func TestLoadConfigFormats(t *testing.T) {
+	want, err := LoadConfig("testcase/global_config.json")
+	if err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+	want.sources = nil
+
+	for _, path := range []string{"testcase/global_config.yaml", "testcase/global_config.toml"} {
+		got, err := LoadConfig(path)
+		if err != nil {
+			t.Errorf("LoadConfig(%s) error: %v", path, err)
+			continue
+		}
+		got.sources = nil
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("LoadConfig(%s) got %+v, want %+v", path, got, want)
+		}
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	var errorCases = []struct {
+		path string
+		want string
+	}{
+		{"testcase/bad_type.json", "testcase/bad_type.json:3:22: json: cannot unmarshal string into lineLimit of type int"},
+		{"testcase/bad_type.yaml", "testcase/bad_type.yaml:2:12: yaml: cannot unmarshal !!str `abc` into int"},
+		{"testcase/bad_type.toml", "testcase/bad_type.toml:2:13: toml: incompatible types"},
+		{"testcase/bad_syntax.yaml", "testcase/bad_syntax.yaml:1:7: yaml: did not find expected"},
+		{"testcase/bad_syntax.toml", "testcase/bad_syntax.toml:2:13: toml: expected value"},
+		{"testcase/unknown_key.json", `testcase/unknown_key.json:3:5: unknown key "bodyRequred"`},
+		{"testcase/unknown_key.yaml", `testcase/unknown_key.yaml:2:1: unknown key "bodyRequred"`},
+		{"testcase/unknown_key.toml", `testcase/unknown_key.toml:2:3: unknown key "bodyRequred"`},
+		{"testcase/negative_limit.yaml", "testcase/negative_limit.yaml:1:1: lineLimit -1 is negative"},
+		{"testcase/empty_scope.toml", "testcase/empty_scope.toml:2:1: scopes contains empty string"},
+		{"testcase/type_overlap.json", `testcase/type_overlap.json:3:5: type "wip" is in both types and denyTypes`},
+		{"testcase", "testcase: is a directory"},
+	}
+	for _, tt := range errorCases {
+		_, err := LoadConfig(tt.path)
+		if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+			t.Errorf("LoadConfig(%s) got error %v, want %q", tt.path, err, tt.want)
+		}
+		if _, ok := err.(*ConfigError); !ok {
+			t.Errorf("LoadConfig(%s) got error type %T, want *ConfigError", tt.path, err)
+		}
+	}
+
+	if _, err := LoadConfig("testcase/not_exist.json"); err != nil {
+		t.Errorf("LoadConfig of missing file got error %v", err)
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	var configCases = []struct {
+		name    string
+		config  *Config
+		wantErr bool
+	}{
+		{"default", NewConfig(), false},
+		{"inherit", &Config{Types: []string{"...", "wip"}, DenyTypes: []string{"..."}}, false},
+		{"negative_limit", &Config{LineLimit: -1}, true},
+		{"empty_type", &Config{Types: []string{""}}, true},
+		{"type_overlap_merged", &Config{Types: []string{"wip"}, DenyTypes: []string{"wip"}}, false},
+		{"scopes", &Config{Scopes: []string{"...", "pkg/*", `/svc-\w+/`}, MultipleScopes: true, ScopeDelimiter: "/"}, false},
+		{"scope_glob", &Config{Scopes: []string{"pkg/["}}, true},
+		{"scope_regexp", &Config{Scopes: []string{`/svc-(\w+/`}}, true},
+		{"scope_delimiter", &Config{ScopeDelimiter: " "}, true},
+		{"type_rules", &Config{TypeRules: map[string]*TypeRule{"*": {DenyScopes: []string{"deps"}}, "docs": {Scope: "forbidden", Body: "optional"}, "ci": {Scopes: []string{"github", "/jenkins-\\w+/"}}}}, false},
+		{"type_rules_scope", &Config{TypeRules: map[string]*TypeRule{"docs": {Scope: "never"}}}, true},
+		{"type_rules_body", &Config{TypeRules: map[string]*TypeRule{"docs": {Body: "forbidden"}}}, true},
+		{"type_rules_pattern", &Config{TypeRules: map[string]*TypeRule{"ci": {Scopes: []string{"gh["}}}}, true},
+		{"type_rules_empty", &Config{TypeRules: map[string]*TypeRule{"ci": {DenyScopes: []string{""}}}}, true},
+		{"scope_paths", &Config{ScopePaths: map[string]string{"services/billing/**": "billing", "**/*.md": "docs"}}, false},
+		{"scope_paths_pattern", &Config{ScopePaths: map[string]string{"services/[": "billing"}}, true},
+		{"scope_paths_empty", &Config{ScopePaths: map[string]string{"services/**": " "}}, true},
+		{"subject", &Config{SubjectCase: caseSentence, DenySubjectEnd: []string{"."}, SubjectMinLength: 5, SubjectMaxLength: 50}, false},
+		{"subject_case", &Config{SubjectCase: "camel"}, true},
+		{"subject_empty_end", &Config{DenySubjectEnd: []string{""}}, true},
+		{"subject_negative_length", &Config{SubjectMinLength: -1}, true},
+		{"subject_max_less_than_min", &Config{SubjectMinLength: 10, SubjectMaxLength: 5}, true},
+		{"breaking_footer", &Config{BreakingFooter: "sometimes"}, true},
+		{"trailers", &Config{Trailers: []string{"...", "Refs"}, RequiredTrailers: []string{"Signed-off-by"}, TrailerPatterns: map[string]string{"Refs": `^#\d+$`}}, false},
+		{"issue", &Config{IssuePattern: `[A-Z]+-\d+`, IssueLocations: []string{"subject", "trailers"}, IssuePrefixes: []string{"PROJ"}}, false},
+		{"issue_pattern", &Config{IssuePattern: `[A-Z+-\d+`}, true},
+		{"issue_location", &Config{IssuePattern: `#\d+`, IssueLocations: []string{"header"}}, true},
+		{"branches", &Config{BranchTicketPattern: `[A-Z]+-\d+`, Branches: map[string]*Config{"release/*": {BodyRequired: true}}}, false},
+		{"branch_pattern", &Config{Branches: map[string]*Config{"release/[": {BodyRequired: true}}}, true},
+		{"branch_override", &Config{Branches: map[string]*Config{"release/*": {LineLimit: -1}}}, true},
+		{"branch_nested", &Config{Branches: map[string]*Config{"release/*": {Branches: map[string]*Config{"*": {}}}}}, true},
+		{"trailer_token", &Config{RequiredTrailers: []string{"Signed off by"}}, true},
+		{"trailer_pattern", &Config{TrailerPatterns: map[string]string{"Refs": `^#(\d+$`}}, true},
+		{"severity", &Config{Severity: map[string]Severity{"LineOverLong": SeverityWarning, "ScopeMissing": SeverityOff}}, false},
+		{"severity_unknown_rule", &Config{Severity: map[string]Severity{"LineTooLong": SeverityWarning}}, true},
+		{"severity_not_rule", &Config{Severity: map[string]Severity{"ConfigError": SeverityOff}}, true},
+		{"severity_part", &Config{Severity: map[string]Severity{"LineOverLong.body": SeverityWarning, "LineOverLong.header": SeverityError}}, false},
+		{"severity_unknown_part", &Config{Severity: map[string]Severity{"LineOverLong.footer": SeverityWarning}}, true},
+		{"severity_part_unknown_rule", &Config{Severity: map[string]Severity{"LineTooLong.body": SeverityWarning}}, true},
+		{"severity_bad_value", &Config{Severity: map[string]Severity{"WrongType": "fatal"}}, true},
+	}
+	for _, tt := range configCases {
+		if err := tt.config.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate got %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	if res, err := Check("feat: some changes", Config{LineLimit: -1}); err == nil || res.State != state.ConfigError {
+		t.Errorf("Check with invalid config got %v, %v, want ConfigError", res.State, err)
+	}
+}
+
+func TestTypeRules(t *testing.T) {
+	cfg, err := LoadConfig("testcase/type_rules.yaml")
+	if err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+
+	var ruleCases = []struct {
+		text string
+		want state.State
+	}{
+		{"ci(github): run tests\n\nbody", state.Validated},
+		{"ci: run tests\n\nbody", state.ScopeMissing},
+		{"ci(api): run tests\n\nbody", state.WrongTypeScope},
+		{"ci(github,api): run tests\n\nbody", state.WrongTypeScope},
+		{"docs: fix typo", state.Validated},
+		{"docs(api): fix typo", state.WrongTypeScope},
+		{"build(deps): bump yaml\n\nbody", state.Validated},
+		{"chore(deps): bump yaml", state.Validated},
+		{"feat(deps): bump yaml\n\nbody", state.WrongTypeScope},
+		{"feat(api, ui): add users\n\nbody", state.Validated},
+		{"feat(api): add users", state.BodyMissing},
+		{"feat(web): add users\n\nbody", state.WrongScope},
+	}
+	for _, tt := range ruleCases {
+		res, err := Check(tt.text, *cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.State != tt.want {
+			t.Errorf("%q: got %v, want %v", tt.text, res.State, tt.want)
+		}
+	}
+
+	header := "feat(api,deps): add users"
+	want := []Violation{violation(state.WrongTypeScope, 1, 10, header, "deps", "feat")}
+	if vs := validateHeader(header, cfg, cfg.typeSet()); !reflect.DeepEqual(vs, want) {
+		t.Errorf("%s: got %+v, want %+v", header, vs, want)
+	}
+}
+
+func TestForBranch(t *testing.T) {
+	cfg, err := LoadConfig("testcase/branches.yaml")
+	if err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+
+	var branchCases = []struct {
+		branch        string
+		bodyRequired  bool
+		scopeRequired bool
+		lineLimit     int
+	}{
+		{"main", true, false, 80},
+		{"release/1.0", false, true, 80},
+		{"release/1.0/rc", true, false, 80},
+		{"hotfix/PROJ-1", true, false, 50},
+		{"", true, false, 80},
+	}
+	for _, tt := range branchCases {
+		got := cfg.ForBranch(tt.branch)
+		if got.BodyRequired != tt.bodyRequired || got.ScopeRequired != tt.scopeRequired || got.LineLimit != tt.lineLimit {
+			t.Errorf("ForBranch(%q) got bodyRequired %v, scopeRequired %v, lineLimit %d", tt.branch, got.BodyRequired, got.ScopeRequired, got.LineLimit)
+		}
+	}
+	if !cfg.BodyRequired || cfg.branch != "" {
+		t.Errorf("ForBranch modified the receiver: %+v", cfg)
+	}
+
+	null, err := LoadConfig("testcase/null_branch.yaml")
+	if err != nil {
+		t.Fatalf("LoadConfig error: %v", err)
+	}
+	if got := null.ForBranch("release/1.0"); !got.BodyRequired || got.branch != "release/1.0" {
+		t.Errorf("ForBranch with a null override got %+v", got)
+	}
+
+	var ticketCases = []struct {
+		branch string
+		text   string
+		want   state.State
+	}{
+		{"feature/PROJ-123-foo", "feat: add foo\n\nbody\n\nRefs: PROJ-123", state.Validated},
+		{"feature/PROJ-123-foo", "feat: add foo for PROJ-123\n\nbody", state.Validated},
+		{"feature/PROJ-123-foo", "feat: add foo for PROJ-1234\n\nbody", state.BranchTicketMissing},
+		{"feature/PROJ-123-foo", "feat: add foo\n\nbody", state.BranchTicketMissing},
+		{"feature/foo", "feat: add foo\n\nbody", state.Validated},
+	}
+	for _, tt := range ticketCases {
+		res, err := Check(tt.text, *cfg.ForBranch(tt.branch))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.State != tt.want {
+			t.Errorf("%q on %s: got %v, want %v", tt.text, tt.branch, res.State, tt.want)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigError is an error in a config file, with the position where it occurs
+type ConfigError struct {
+	Path   string
+	Line   int // 1-based, 0 if unknown
+	Column int // 1-based, 0 if unknown
+	Msg    string
+}
+
+func (e *ConfigError) Error() string {
+	switch {
+	case e.Line > 0 && e.Column > 0:
+		return fmt.Sprintf("%s:%d:%d: %s", e.Path, e.Line, e.Column, e.Msg)
+	case e.Line > 0:
+		return fmt.Sprintf("%s:%d: %s", e.Path, e.Line, e.Msg)
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Msg)
+}
+
+// key is a top level key in a config file, with its position
+type key struct {
+	name   string
+	line   int
+	column int
+}
+
+// format of config file, keys lists the top level keys, decode decodes strictly,
+// decodeMap decodes into a generic map, telling the keys at all levels
+type format struct {
+	keys      func(buf []byte) ([]key, error)
+	decode    func(buf []byte, cfg *Config) error
+	decodeMap func(buf []byte, m *map[string]interface{}) error
+}
+
+var (
+	// formats of config file, chosen by file extension
+	formats = map[string]format{
+		".json": {jsonKeys, decodeJSON, decodeJSONMap},
+		".yaml": {yamlKeys, decodeYAML, decodeYAMLMap},
+		".yml":  {yamlKeys, decodeYAML, decodeYAMLMap},
+		".toml": {tomlKeys, decodeTOML, decodeTOMLMap},
+	}
+
+	linePattern = regexp.MustCompile(`^(yaml|toml): line (\d+)(?: \(last key "[^"]*"\))?: `)
+)
+
+// formatOf returns the format for the extension of path,
+// JSON is assumed for unknown extensions.
+func formatOf(path string) format {
+	if f, ok := formats[strings.ToLower(filepath.Ext(path))]; ok {
+		return f
+	}
+	return formats[".json"]
+}
+
+func jsonKeys(buf []byte) ([]key, error) {
+	dec := json.NewDecoder(bytes.NewReader(buf))
+	if t, err := dec.Token(); err == io.EOF {
+		return nil, &ConfigError{Msg: "json: config is empty"}
+	} else if err != nil || t != json.Delim('{') {
+		return nil, jsonError(buf, err, "json: config must be an object")
+	}
+
+	keys := make([]key, 0)
+	for dec.More() {
+		start := int(dec.InputOffset())
+		t, err := dec.Token()
+		if err != nil {
+			return nil, jsonError(buf, err, "")
+		}
+
+		// skip the comma and whitespaces before the key
+		start += bytes.IndexByte(buf[start:], '"')
+		line, col := position(buf, start)
+		keys = append(keys, key{name: t.(string), line: line, column: col})
+
+		var v json.RawMessage
+		if err := dec.Decode(&v); err != nil {
+			return nil, jsonError(buf, err, "")
+		}
+	}
+	return keys, nil
+}
+
+func decodeJSON(buf []byte, cfg *Config) error {
+	dec := json.NewDecoder(bytes.NewReader(buf))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(cfg); err != nil {
+		return jsonError(buf, err, "")
+	}
+	return nil
+}
+
+func decodeJSONMap(buf []byte, m *map[string]interface{}) error {
+	if err := json.Unmarshal(buf, m); err != nil {
+		return jsonError(buf, err, "")
+	}
+	return nil
+}
+
+// jsonError converts the errors of encoding/json, which only tell the byte offset,
+// to ConfigError with line and column. msg is used if err is nil.
+func jsonError(buf []byte, err error, msg string) error {
+	var offset int64
+	switch e := err.(type) {
+	case nil:
+		return &ConfigError{Line: 1, Column: 1, Msg: msg}
+	case *json.SyntaxError:
+		offset = e.Offset
+	case *json.UnmarshalTypeError:
+		offset = e.Offset
+		err = fmt.Errorf("json: cannot unmarshal %s into %s of type %s", e.Value, e.Field, e.Type)
+	default:
+		return &ConfigError{Msg: err.Error()}
+	}
+
+	line, col := position(buf, int(offset))
+	return &ConfigError{Line: line, Column: col, Msg: err.Error()}
+}
+
+// position converts byte offset in buf to 1-based line and column
+func position(buf []byte, offset int) (line, col int) {
+	if offset > len(buf) {
+		offset = len(buf)
+	}
+	before := buf[:offset]
+	start := bytes.LastIndexByte(before, '\n') + 1
+	return bytes.Count(before, []byte("\n")) + 1, utf8.RuneCount(before[start:]) + 1
+}
+
+// lineError converts the errors telling "line N" in the message to ConfigError,
+// the column is guessed as where the value starts in that line.
+func lineError(buf []byte, msg string) error {
+	m := linePattern.FindStringSubmatch(msg)
+	if m == nil {
+		return &ConfigError{Msg: msg}
+	}
+
+	line, _ := strconv.Atoi(m[2])
+	return &ConfigError{Line: line, Column: valueColumn(buf, line), Msg: m[1] + ": " + msg[len(m[0]):]}
+}
+
+// valueColumn returns the column of the value in a "key: value" or "key = value" line
+func valueColumn(buf []byte, line int) int {
+	lines := strings.Split(string(buf), "\n")
+	if line < 1 || line > len(lines) {
+		return 0
+	}
+
+	text := lines[line-1]
+	sep := strings.IndexAny(text, ":=")
+	if sep < 0 {
+		return 0
+	}
+	offset := sep + 1
+	offset += len(text[offset:]) - len(strings.TrimLeft(text[offset:], " \t"))
+	return column(text, offset)
+}
+
+func yamlKeys(buf []byte) ([]key, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(buf, &doc); err != nil {
+		return nil, lineError(buf, err.Error())
+	}
+
+	if len(doc.Content) == 0 {
+		// empty file
+		return nil, nil
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, &ConfigError{Line: root.Line, Column: root.Column, Msg: "yaml: config must be a mapping"}
+	}
+
+	keys := make([]key, 0, len(root.Content)/2)
+	for i := 0; i < len(root.Content); i += 2 {
+		k := root.Content[i]
+		keys = append(keys, key{name: k.Value, line: k.Line, column: k.Column})
+	}
+	return keys, nil
+}
+
+func decodeYAML(buf []byte, cfg *Config) error {
+	dec := yaml.NewDecoder(bytes.NewReader(buf))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil {
+		if te, ok := err.(*yaml.TypeError); ok && len(te.Errors) > 0 {
+			return lineError(buf, "yaml: "+te.Errors[0])
+		}
+		if err == io.EOF {
+			// empty file
+			return nil
+		}
+		return lineError(buf, err.Error())
+	}
+	return nil
+}
+
+func decodeYAMLMap(buf []byte, m *map[string]interface{}) error {
+	if err := yaml.Unmarshal(buf, m); err != nil {
+		return lineError(buf, err.Error())
+	}
+	return nil
+}
+
+func tomlKeys(buf []byte) ([]key, error) {
+	md, err := toml.Decode(string(buf), &map[string]interface{}{})
+	if err != nil {
+		return nil, tomlError(buf, err)
+	}
+
+	keys := make([]key, 0)
+	for _, k := range md.Keys() {
+		if len(k) == 1 {
+			line, col := tomlKeyPosition(buf, k[0])
+			keys = append(keys, key{name: k[0], line: line, column: col})
+		}
+	}
+	return keys, nil
+}
+
+// tomlKeyPosition finds where the top level key is defined,
+// as the toml package does not tell the position of keys.
+func tomlKeyPosition(buf []byte, name string) (line, col int) {
+	q := regexp.QuoteMeta(name)
+	re := regexp.MustCompile(`(?m)^[ \t]*(?:\[[ \t]*)?(?:` + q + `|"` + q + `"|'` + q + `')[ \t]*[=\].]`)
+	loc := re.FindIndex(buf)
+	if loc == nil {
+		return 0, 0
+	}
+
+	start := loc[0] + len(buf[loc[0]:loc[1]]) - len(bytes.TrimLeft(buf[loc[0]:loc[1]], " \t["))
+	return position(buf, start)
+}
+
+func decodeTOML(buf []byte, cfg *Config) error {
+	md, err := toml.Decode(string(buf), cfg)
+	if err != nil {
+		return tomlError(buf, err)
+	}
+
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		return &ConfigError{Msg: fmt.Sprintf("toml: unknown key %q", undecoded[0].String())}
+	}
+	return nil
+}
+
+func decodeTOMLMap(buf []byte, m *map[string]interface{}) error {
+	if _, err := toml.Decode(string(buf), m); err != nil {
+		return tomlError(buf, err)
+	}
+	return nil
+}
+
+func tomlError(buf []byte, err error) error {
+	if pe, ok := err.(toml.ParseError); ok {
+		line, col := position(buf, pe.Position.Start)
+		// the line told in the message may differ from the position
+		msg := linePattern.ReplaceAllString(pe.Error(), "$1: ")
+		return &ConfigError{Line: line, Column: col, Msg: msg}
+	}
+	return lineError(buf, err.Error())
+}
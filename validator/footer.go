@@ -0,0 +1,190 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/JayceChant/commit-msg/state"
+)
+
+const (
+	// trailerPattern matches a footer line, Token: value or Token #value,
+	// where token contains no whitespace except BREAKING CHANGE
+	trailerPattern = `^(BREAKING CHANGE|[A-Za-z0-9][\w-]*)(: | #)(.*)$`
+	// malformedTrailerPattern matches a line looks like a trailer lacking the space after colon,
+	// e.g. Refs:#12, but not an URL or a C++ name like std::move
+	malformedTrailerPattern = `^[A-Za-z0-9][\w-]*:[^\s/:]`
+)
+
+var (
+	trailerRegexp          = regexp.MustCompile(trailerPattern)
+	malformedTrailerRegexp = regexp.MustCompile(malformedTrailerPattern)
+)
+
+// Trailer is a footer of a commit message, in the form of Token: value or Token #value
+type Trailer struct {
+	Token string
+	// Separator is ": " or " #"
+	Separator string
+	// Value may contain several lines, as the lines following the trailer,
+	// but not a trailer itself, are taken as part of the value
+	Value string
+	// Line is the 1-based number of the line the trailer starts at
+	Line int
+}
+
+// isBreaking reports whether the trailer tells a breaking change
+func (t *Trailer) isBreaking() bool {
+	return t.Separator == ": " && (t.Token == "BREAKING CHANGE" || t.Token == "BREAKING-CHANGE")
+}
+
+// parseTrailers splits the footer from body, the rest of the message after the header,
+// as git interpret-trailers does. The footer is the last paragraph, if it begins with
+// a trailer, well-formed or not, and either contains a well-formed one or is made of
+// trailers only. A line not a trailer
+// is taken as part of the value of the previous one. Lines in the footer look like
+// a trailer but not well-formed are reported.
+func parseTrailers(body string) ([]Trailer, []Violation) {
+	lines := strings.Split(body, "\n")
+	end := len(lines)
+	for end > 0 && isEmpty(lines[end-1]) {
+		end--
+	}
+	start := end
+	for start > 0 && !isEmpty(lines[start-1]) {
+		start--
+	}
+	if start == 0 || start == end || !isTrailerBlock(lines[start:end]) {
+		return nil, nil
+	}
+
+	var trailers []Trailer
+	var vs []Violation
+	for i := start; i < end; i++ {
+		if groups := trailerRegexp.FindStringSubmatch(lines[i]); groups != nil {
+			trailers = append(trailers, Trailer{Token: groups[1], Separator: groups[2], Value: groups[3], Line: i + 2})
+			continue
+		}
+
+		if malformedTrailerRegexp.MatchString(lines[i]) {
+			vs = append(vs, violation(state.MalformedTrailer, i+2, 1, lines[i], lines[i]))
+		}
+		if len(trailers) > 0 {
+			trailers[len(trailers)-1].Value += "\n" + lines[i]
+		}
+	}
+
+	for i := range trailers {
+		trailers[i].Value = strings.TrimRight(trailers[i].Value, "\n")
+	}
+	return trailers, vs
+}
+
+// isTrailerBlock reports whether the paragraph begins with a trailer, well-formed or not,
+// and either contains a well-formed one or is made of malformed ones only
+func isTrailerBlock(paragraph []string) bool {
+	if !isTrailerLike(paragraph[0]) {
+		return false
+	}
+	malformedOnly := true
+	for _, line := range paragraph {
+		if trailerRegexp.MatchString(line) {
+			return true
+		}
+		malformedOnly = malformedOnly && isTrailerLike(line)
+	}
+	return malformedOnly
+}
+
+// isTrailerLike reports whether the line is a trailer, well-formed or not
+func isTrailerLike(line string) bool {
+	return trailerRegexp.MatchString(line) || malformedTrailerRegexp.MatchString(line)
+}
+
+// validateTrailers checks the trailers against the allowed tokens,
+// the patterns of values and the required tokens
+func validateTrailers(trailers []Trailer, config *Config) []Violation {
+	var vs []Violation
+	present := make(map[string]bool, len(trailers))
+	for _, t := range trailers {
+		present[strings.ToLower(t.Token)] = true
+		text := t.Token + t.Separator + firstLine(t.Value)
+		if t.isBreaking() {
+			continue
+		}
+
+		if len(config.Trailers) > 0 && !containsFold(config.Trailers, t.Token) {
+			vs = append(vs, violation(state.WrongTrailer, t.Line, 1, text, t.Token, strings.Join(config.Trailers, ", ")))
+			continue
+		}
+
+		if pattern, ok := lookupFold(config.TrailerPatterns, t.Token); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(t.Value) {
+				vs = append(vs, violation(state.BadTrailerValue, t.Line, len(t.Token+t.Separator)+1, text, t.Token, pattern, text))
+			}
+		}
+	}
+
+	for _, token := range config.RequiredTrailers {
+		if !present[strings.ToLower(token)] {
+			vs = append(vs, violation(state.TrailerMissing, 0, 0, "", token))
+		}
+	}
+	return vs
+}
+
+// trailerProblems checks the trailer tokens and the patterns of values
+func (cfg *Config) trailerProblems() []problem {
+	var ps []problem
+	for _, l := range []struct {
+		key  string
+		list []string
+	}{
+		{"trailers", cfg.Trailers},
+		{"requiredTrailers", cfg.RequiredTrailers},
+	} {
+		for _, token := range l.list {
+			if token != inherit && token != "" && !trailerRegexp.MatchString(token+": ") {
+				ps = append(ps, problem{l.key, fmt.Sprintf("%s contains invalid token %q", l.key, token)})
+			}
+		}
+	}
+
+	tokens := make([]string, 0, len(cfg.TrailerPatterns))
+	for token := range cfg.TrailerPatterns {
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+	for _, token := range tokens {
+		if _, err := regexp.Compile(cfg.TrailerPatterns[token]); err != nil {
+			ps = append(ps, problem{"trailerPatterns", fmt.Sprintf("pattern of trailer %s: %v", token, err)})
+		}
+	}
+	return ps
+}
+
+func firstLine(s string) string {
+	return strings.SplitN(s, "\n", 2)[0]
+}
+
+// containsFold reports whether list contains s, case-insensitively
+func containsFold(list []string, s string) bool {
+	for _, e := range list {
+		if strings.EqualFold(e, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupFold returns the value of key in m, case-insensitively
+func lookupFold(m map[string]string, key string) (string, bool) {
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,123 @@
+package validator
+
+import (
+	"strings"
+	"unicode"
+)
+
+// baseVerbs are the verbs common in commit subjects, in imperative mood
+var baseVerbs = toSet(strings.Fields(`
+	accept access add adapt adjust align allow alter append apply archive assert avoid
+	break bring build bump cache call cancel change check clarify clean cleanup clear
+	close collect combine comment commit compile complete compute configure connect
+	consolidate convert copy correct create deal debug declare decouple default define
+	delete deploy deprecate describe detect disable display document drop dump edit
+	embed emit enable enforce enhance ensure escape exclude expand expect export expose
+	extend extract fetch fill filter finish fix flatten flush fold force format forward
+	generate get handle hide highlight ignore implement import improve include increase
+	init initialize inject inline insert install integrate introduce invert
+	isolate keep limit lint list load localize lock log lower make mark merge migrate
+	minimize mock modify move name normalize note open optimize order organize output
+	override parse pass patch pin polish populate port prefer prepare prevent print
+	process protect provide prune publish pull push put raise read rebase rebuild
+	record reduce refactor refine reformat register reject release reload remove rename
+	reorder reorganize repair replace report require reset resolve restore restrict
+	restructure retry return reuse revert review revise rewrite rework roll run save
+	scan schedule separate serialize set setup show simplify skip sort specify split
+	start stop store streamline strip support suppress switch sync test tidy track
+	translate trim tune tweak unify uninstall unlock unpin update upgrade use validate
+	verify wrap write
+`))
+
+// irregularVerbs maps the irregular forms to the imperative ones
+var irregularVerbs = map[string]string{
+	"broke":     "break",
+	"broken":    "break",
+	"brought":   "bring",
+	"built":     "build",
+	"did":       "do",
+	"does":      "do",
+	"done":      "do",
+	"doing":     "do",
+	"got":       "get",
+	"gotten":    "get",
+	"had":       "have",
+	"has":       "have",
+	"kept":      "keep",
+	"made":      "make",
+	"put":       "put",
+	"ran":       "run",
+	"rebuilt":   "rebuild",
+	"reset":     "reset",
+	"rewrote":   "rewrite",
+	"rewritten": "rewrite",
+	"set":       "set",
+	"split":     "split",
+	"took":      "take",
+	"taken":     "take",
+	"wrote":     "write",
+	"written":   "write",
+}
+
+func toSet(list []string) map[string]dummy {
+	set := make(map[string]dummy, len(list))
+	for _, s := range list {
+		set[s] = dummy{}
+	}
+	return set
+}
+
+// imperative returns the imperative form of word if it is a known verb
+// in past tense, gerund or third person, or "" if it is not.
+func imperative(word string) string {
+	word = strings.ToLower(word)
+	if _, ok := baseVerbs[word]; ok {
+		return ""
+	}
+	if v, ok := irregularVerbs[word]; ok {
+		return v
+	}
+
+	for _, c := range stemCandidates(word) {
+		if _, ok := baseVerbs[c]; ok {
+			return c
+		}
+	}
+	return ""
+}
+
+// stemCandidates guesses the imperative forms of word by its suffix,
+// e.g. "added" -> "add", "updated" -> "update", "stopped" -> "stop", "copied" -> "copy"
+func stemCandidates(word string) []string {
+	var candidates []string
+	for _, suffix := range []string{"ed", "ing", "es", "s"} {
+		if !strings.HasSuffix(word, suffix) {
+			continue
+		}
+
+		stem := strings.TrimSuffix(word, suffix)
+		candidates = append(candidates, stem, stem+"e")
+		if n := len(stem); n >= 2 && stem[n-1] == stem[n-2] {
+			// doubled consonant
+			candidates = append(candidates, stem[:n-1])
+		}
+		if strings.HasSuffix(stem, "i") && suffix != "ing" {
+			candidates = append(candidates, strings.TrimSuffix(stem, "i")+"y")
+		}
+	}
+	return candidates
+}
+
+// firstWord returns the first word of s and its byte offset,
+// a word being a sequence of letters
+func firstWord(s string) (string, int) {
+	start := strings.IndexFunc(s, unicode.IsLetter)
+	if start < 0 {
+		return "", -1
+	}
+	end := strings.IndexFunc(s[start:], func(r rune) bool { return !unicode.IsLetter(r) })
+	if end < 0 {
+		return s[start:], start
+	}
+	return s[start : start+end], start
+}
@@ -0,0 +1,117 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/JayceChant/commit-msg/state"
+)
+
+// places where an issue reference may appear
+const (
+	inSubject  = "subject"
+	inBody     = "body"
+	inTrailers = "trailers"
+)
+
+// issueLocations are the places searched if IssueLocations is empty
+var issueLocations = []string{inSubject, inBody, inTrailers}
+
+// validateIssueRef checks that the message references an issue matching IssuePattern,
+// with one of IssuePrefixes if any, in the places told by IssueLocations.
+// body is the rest of the message after the header.
+func validateIssueRef(header, body string, trailers []Trailer, config *Config) []Violation {
+	if config.IssuePattern == "" {
+		return nil
+	}
+	re, err := regexp.Compile(config.IssuePattern)
+	if err != nil {
+		return nil
+	}
+
+	locations := config.IssueLocations
+	if len(locations) == 0 {
+		locations = issueLocations
+	}
+
+	for _, loc := range locations {
+		for _, text := range issueTexts(loc, header, body, trailers, config) {
+			for _, ref := range re.FindAllString(text, -1) {
+				if hasIssuePrefix(ref, config.IssuePrefixes) {
+					return nil
+				}
+			}
+		}
+	}
+	return []Violation{violation(state.IssueRefMissing, 0, 0, "", strings.Join(locations, ", "), issueForm(config))}
+}
+
+// issueTexts returns the texts of the message in location loc
+func issueTexts(loc, header, body string, trailers []Trailer, config *Config) []string {
+	switch loc {
+	case inSubject:
+		if h := parseHeader(header, config); h != nil {
+			return []string{h.Subject}
+		}
+		return []string{header}
+	case inBody:
+		// the body ends where the footer starts
+		lines := strings.Split(body, "\n")
+		if len(trailers) > 0 {
+			lines = lines[:trailers[0].Line-2]
+		}
+		return []string{strings.Join(lines, "\n")}
+	case inTrailers:
+		var texts []string
+		for _, t := range trailers {
+			if len(config.IssueTrailers) == 0 || containsFold(config.IssueTrailers, t.Token) {
+				texts = append(texts, t.Value)
+			}
+		}
+		return texts
+	}
+	return nil
+}
+
+// hasIssuePrefix reports whether ref starts with one of prefixes, followed by
+// a character other than letters and digits, or prefixes is empty
+func hasIssuePrefix(ref string, prefixes []string) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+	for _, p := range prefixes {
+		if strings.HasPrefix(ref, p) && (len(ref) == len(p) || !isAlnum(ref[len(p)])) {
+			return true
+		}
+	}
+	return false
+}
+
+func isAlnum(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// issueForm describes the issue reference expected, for the hint
+func issueForm(config *Config) string {
+	if len(config.IssuePrefixes) == 0 {
+		return config.IssuePattern
+	}
+	return fmt.Sprintf("%s, starting with %s", config.IssuePattern, strings.Join(config.IssuePrefixes, ", "))
+}
+
+// issueProblems checks the pattern and locations of issue references
+func (cfg *Config) issueProblems() []problem {
+	var ps []problem
+	if _, err := regexp.Compile(cfg.IssuePattern); err != nil {
+		ps = append(ps, problem{"issuePattern", fmt.Sprintf("issuePattern: %v", err)})
+	}
+	for _, loc := range cfg.IssueLocations {
+		switch loc {
+		case inSubject, inBody, inTrailers, inherit:
+		default:
+			ps = append(ps, problem{"issueLocations", fmt.Sprintf("issue location %q should be subject, body or trailers", loc)})
+		}
+	}
+	return ps
+}
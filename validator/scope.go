@@ -0,0 +1,258 @@
+package validator
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/JayceChant/commit-msg/dir"
+	"github.com/JayceChant/commit-msg/state"
+)
+
+// defaultScopeDelimiter separates the scopes of a header if MultipleScopes is true
+const defaultScopeDelimiter = ","
+
+// scopePart is one of the scopes of a header, with its byte offset in the header
+type scopePart struct {
+	scope  string
+	offset int
+}
+
+// splitScopes splits the scope of a header starting at offset by the delimiter,
+// the scopes are trimmed of spaces. The scope is taken as a whole unless MultipleScopes is true.
+func (cfg *Config) splitScopes(scope string, offset int) []scopePart {
+	if !cfg.MultipleScopes {
+		return []scopePart{{scope, offset}}
+	}
+
+	delim := cfg.ScopeDelimiter
+	if delim == "" {
+		delim = defaultScopeDelimiter
+	}
+
+	var parts []scopePart
+	for _, s := range strings.Split(scope, delim) {
+		trimmed := strings.TrimLeft(s, " \t")
+		parts = append(parts, scopePart{strings.TrimRight(trimmed, " \t"), offset + len(s) - len(trimmed)})
+		offset += len(s) + len(delim)
+	}
+	return parts
+}
+
+// isScopeRegexp reports whether the entry of Scopes is a regular expression enclosed in slashes
+func isScopeRegexp(entry string) bool {
+	return len(entry) > 2 && entry[0] == '/' && entry[len(entry)-1] == '/'
+}
+
+// matchScope reports whether scope matches the entry of Scopes, which is
+// a regular expression enclosed in slashes matching the whole scope, e.g. /^pkg-\w+$/,
+// a glob pattern, e.g. pkg/*, or the scope keyword itself.
+func matchScope(entry, scope string) bool {
+	if isScopeRegexp(entry) {
+		re, err := regexp.Compile(`^(?:` + entry[1:len(entry)-1] + `)$`)
+		return err == nil && re.MatchString(scope)
+	}
+	if entry == scope {
+		return true
+	}
+	ok, _ := path.Match(entry, scope)
+	return ok
+}
+
+// validateScope checks the scope of header between start and end, every one of the scopes
+// separately if MultipleScopes is true, start and end are the same if there is no scope.
+func validateScope(header string, start, end int, config *Config) []Violation {
+	scope := header[start:end]
+	var vs []Violation
+	if isEmpty(scope) {
+		if config.ScopeRequired {
+			vs = append(vs, violation(state.ScopeMissing, 1, column(header, start), header))
+		}
+		// suggest the scope if the changes are mapped to any
+		if missing, want := config.uncoveredScopes(nil); missing {
+			vs = append(vs, violation(state.ScopeMismatch, 1, column(header, start), header, scope, want))
+		}
+		return vs
+	}
+
+	parts := config.splitScopes(scope, start)
+	for _, p := range parts {
+		if p.scope == "" {
+			// e.g. feat(api,): x, whether scopes is set or not
+			vs = append(vs, violation(state.BadHeaderFormat, 1, column(header, p.offset), header, header))
+		} else if len(config.Scopes) > 0 && !scopeAllowed(p.scope, config.Scopes) {
+			vs = append(vs, violation(state.WrongScope, 1, column(header, p.offset), header, p.scope, strings.Join(config.Scopes, ", ")))
+		}
+	}
+
+	if missing, want := config.uncoveredScopes(parts); missing {
+		vs = append(vs, violation(state.ScopeMismatch, 1, column(header, start), header, scope, want))
+	}
+	return vs
+}
+
+func scopeAllowed(scope string, entries []string) bool {
+	for _, e := range entries {
+		if matchScope(e, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeEntryError checks the regular expression or the glob pattern of the entry of Scopes
+func scopeEntryError(entry string) error {
+	if isScopeRegexp(entry) {
+		_, err := regexp.Compile(entry[1 : len(entry)-1])
+		return err
+	}
+	_, err := path.Match(entry, "")
+	return err
+}
+
+// scopeProblems checks the patterns of Scopes and the delimiter
+func (cfg *Config) scopeProblems() []problem {
+	var ps []problem
+	for _, e := range cfg.Scopes {
+		if err := scopeEntryError(e); err != nil {
+			ps = append(ps, problem{"scopes", fmt.Sprintf("scopes: %s: %v", e, err)})
+		}
+	}
+
+	patterns := make([]string, 0, len(cfg.ScopePaths))
+	for p := range cfg.ScopePaths {
+		patterns = append(patterns, p)
+	}
+	sort.Strings(patterns)
+	for _, p := range patterns {
+		if _, err := path.Match(strings.Replace(p, "**", "*", -1), ""); err != nil {
+			ps = append(ps, problem{"scopePaths", fmt.Sprintf("scopePaths: %s: %v", p, err)})
+		}
+		if isEmpty(cfg.ScopePaths[p]) {
+			ps = append(ps, problem{"scopePaths", fmt.Sprintf("scopePaths: %s maps to empty scope", p)})
+		}
+	}
+
+	if strings.TrimSpace(cfg.ScopeDelimiter) != cfg.ScopeDelimiter || strings.ContainsAny(cfg.ScopeDelimiter, "()") {
+		ps = append(ps, problem{"scopeDelimiter", fmt.Sprintf("scopeDelimiter %q should not start or end with spaces, or contain parentheses", cfg.ScopeDelimiter)})
+	}
+	return ps
+}
+
+// ForFiles returns a new config with the changed files of the commit, by which the scope
+// is checked against ScopePaths, see PathScope. cfg is not modified.
+func (cfg *Config) ForFiles(files []string) *Config {
+	merged := cfg.Merge(&Config{})
+	merged.files = files
+	return merged
+}
+
+// ForStagedFiles returns a new config with the files staged for the commit if ScopePaths is set,
+// see ForFiles, or cfg itself if not. The scope is not checked against ScopePaths if git fails.
+func (cfg *Config) ForStagedFiles() *Config {
+	if len(cfg.ScopePaths) == 0 {
+		return cfg
+	}
+	files, _ := dir.StagedFiles()
+	return cfg.ForFiles(files)
+}
+
+// hookMessage is the message file git passes to the commit-msg hook
+const hookMessage = "COMMIT_EDITMSG"
+
+// isHookMessage reports whether file is the message of the commit being made,
+// passed by git to the hook, the staged files are only relevant to it
+func isHookMessage(file string) bool {
+	return filepath.Base(file) == hookMessage
+}
+
+// PathScope returns the scope of the file by ScopePaths, that of the longest pattern
+// matching if several do, "" if none.
+func (cfg *Config) PathScope(file string) string {
+	best := ""
+	for p := range cfg.ScopePaths {
+		if len(p) > len(best) || (len(p) == len(best) && p < best) {
+			if matchPath(p, file) {
+				best = p
+			}
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return cfg.ScopePaths[best]
+}
+
+// uncoveredScopes reports whether the scopes of the changed files by ScopePaths
+// are not all in the parts of the header, none for a header without scope,
+// along with the scope covering them all. A header takes a single scope unless
+// MultipleScopes is true, so any one of the scopes of the changes is enough then,
+// and the first of them is suggested.
+func (cfg *Config) uncoveredScopes(parts []scopePart) (bool, string) {
+	if len(cfg.ScopePaths) == 0 || len(cfg.files) == 0 {
+		return false, ""
+	}
+
+	header := make(map[string]bool, len(parts))
+	for _, p := range parts {
+		header[p.scope] = true
+	}
+
+	seen := make(map[string]bool)
+	var want []string
+	missing, covered := false, false
+	for _, f := range cfg.files {
+		s := cfg.PathScope(f)
+		if s == "" || seen[s] {
+			continue
+		}
+		seen[s] = true
+		want = append(want, s)
+		missing = missing || !header[s]
+		covered = covered || header[s]
+	}
+	if len(want) == 0 {
+		return false, ""
+	}
+	sort.Strings(want)
+
+	if !cfg.MultipleScopes {
+		return !covered, want[0]
+	}
+
+	delim := cfg.ScopeDelimiter
+	if delim == "" {
+		delim = defaultScopeDelimiter
+	}
+	return missing, strings.Join(want, delim)
+}
+
+// matchPath reports whether the slash-separated path name matches pattern,
+// in which "**" matches zero or more directories, other elements are matched by path.Match.
+func matchPath(pattern, name string) bool {
+	return matchElems(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchElems(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := len(name); i >= 0; i-- {
+				if matchElems(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
@@ -0,0 +1,103 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/JayceChant/commit-msg/state"
+)
+
+// Severity tells how a broken rule is treated
+type Severity string
+
+// severities of rules
+const (
+	// SeverityError fails the message, the default of every rule
+	SeverityError Severity = "error"
+	// SeverityWarning reports the violation without failing the message
+	SeverityWarning Severity = "warning"
+	// SeverityOff skips the rule
+	SeverityOff Severity = "off"
+)
+
+// parts of the message a rule may be limited to, e.g. LineOverLong.body
+const (
+	partHeader = "header"
+	partBody   = "body"
+)
+
+// severityOf returns the severity of the violation v in the config, error if not set.
+// The severity of the rule in the part of the message v is on, e.g. LineOverLong.body,
+// takes precedence over that of the rule, e.g. LineOverLong.
+func (cfg *Config) severityOf(v Violation) Severity {
+	if part := partOf(v); part != "" {
+		if sev, ok := cfg.Severity[v.State.String()+"."+part]; ok {
+			return sev
+		}
+	}
+	if sev, ok := cfg.Severity[v.State.String()]; ok {
+		return sev
+	}
+	return SeverityError
+}
+
+// partOf returns the part of the message the violation is on, "" if not bound to a line.
+// The footer is taken as part of the body.
+func partOf(v Violation) string {
+	switch {
+	case v.Line == 1:
+		return partHeader
+	case v.Line > 1:
+		return partBody
+	}
+	return ""
+}
+
+// applySeverity sets the severity of each violation by the config,
+// and drops those of the rules turned off
+func applySeverity(vs []Violation, cfg *Config) []Violation {
+	var applied []Violation
+	for _, v := range vs {
+		v.Severity = cfg.severityOf(v)
+		if v.Severity != SeverityOff {
+			applied = append(applied, v)
+		}
+	}
+	return applied
+}
+
+// severityProblems checks the rule names and the severities of the config
+func (cfg *Config) severityProblems() []problem {
+	names := make([]string, 0, len(cfg.Severity))
+	for name := range cfg.Severity {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var ps []problem
+	for _, name := range names {
+		sev := cfg.Severity[name]
+		rule := name
+		if i := strings.LastIndexByte(name, '.'); i >= 0 {
+			if part := name[i+1:]; part != partHeader && part != partBody {
+				ps = append(ps, problem{"severity", fmt.Sprintf("severity of %q: part %q should be header or body", name, part)})
+				continue
+			}
+			rule = name[:i]
+		}
+
+		var s state.State
+		if err := s.UnmarshalText([]byte(rule)); err != nil || !s.IsFormatError() || s == state.UndefindedError {
+			ps = append(ps, problem{"severity", fmt.Sprintf("severity of unknown rule %q", name)})
+			continue
+		}
+
+		switch sev {
+		case SeverityError, SeverityWarning, SeverityOff:
+		default:
+			ps = append(ps, problem{"severity", fmt.Sprintf("severity %q of %s should be error, warning or off", sev, name)})
+		}
+	}
+	return ps
+}
@@ -0,0 +1,121 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/JayceChant/commit-msg/state"
+)
+
+// anyType is the key of TypeRules for the types not listed
+const anyType = "*"
+
+// requirements of scope and body in TypeRule
+const (
+	requireRequired  = "required"
+	requireOptional  = "optional"
+	requireForbidden = "forbidden"
+)
+
+// TypeRule is the rule of scope and body for a type, see Config.TypeRules
+type TypeRule struct {
+	// Scope tells whether the scope is required, optional or forbidden with the type,
+	// scopeRequired applies if empty
+	Scope string `json:"scope,omitempty" yaml:"scope,omitempty" toml:"scope,omitempty"`
+	// Scopes are the scopes allowed with the type, in the forms of Config.Scopes,
+	// checked in addition to Config.Scopes, any if empty
+	Scopes []string `json:"scopes,omitempty" yaml:"scopes,omitempty" toml:"scopes,omitempty"`
+	// DenyScopes are the scopes not allowed with the type, in the forms of Config.Scopes
+	DenyScopes []string `json:"denyScopes,omitempty" yaml:"denyScopes,omitempty" toml:"denyScopes,omitempty"`
+	// Body tells whether the body is required or optional with the type, bodyRequired applies if empty
+	Body string `json:"body,omitempty" yaml:"body,omitempty" toml:"body,omitempty"`
+}
+
+// typeRule returns the rule of typ in TypeRules, that of "*" if typ is not listed, nil if neither
+func (cfg *Config) typeRule(typ string) *TypeRule {
+	if r, ok := cfg.TypeRules[typ]; ok {
+		return r
+	}
+	return cfg.TypeRules[anyType]
+}
+
+// forType returns cfg with ScopeRequired and BodyRequired set by the rule of typ,
+// or cfg itself if the rule sets neither. cfg is not modified.
+func (cfg *Config) forType(typ string) *Config {
+	r := cfg.typeRule(typ)
+	if r == nil || (r.Scope == "" && r.Body == "") {
+		return cfg
+	}
+
+	merged := *cfg
+	if r.Scope != "" {
+		merged.ScopeRequired = r.Scope == requireRequired
+	}
+	if r.Body != "" {
+		merged.BodyRequired = r.Body == requireRequired
+	}
+	return &merged
+}
+
+// validateTypeScope checks the scope of header between start and end against the rule of typ,
+// every one of the scopes separately if MultipleScopes is true.
+func validateTypeScope(header, typ string, start, end int, config *Config) []Violation {
+	r := config.typeRule(typ)
+	scope := header[start:end]
+	if r == nil || isEmpty(scope) {
+		return nil
+	}
+
+	if r.Scope == requireForbidden {
+		return []Violation{violation(state.WrongTypeScope, 1, column(header, start), header, scope, typ)}
+	}
+
+	var vs []Violation
+	for _, p := range config.splitScopes(scope, start) {
+		if p.scope == "" {
+			// reported by validateScope as BadHeaderFormat
+			continue
+		}
+		if (len(r.Scopes) > 0 && !scopeAllowed(p.scope, r.Scopes)) || scopeAllowed(p.scope, r.DenyScopes) {
+			vs = append(vs, violation(state.WrongTypeScope, 1, column(header, p.offset), header, p.scope, typ))
+		}
+	}
+	return vs
+}
+
+// typeRuleProblems checks the requirements and the scope patterns of TypeRules
+func (cfg *Config) typeRuleProblems() []problem {
+	types := make([]string, 0, len(cfg.TypeRules))
+	for t := range cfg.TypeRules {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	var ps []problem
+	for _, t := range types {
+		r := cfg.TypeRules[t]
+		if r == nil {
+			continue
+		}
+
+		switch r.Scope {
+		case "", requireRequired, requireOptional, requireForbidden:
+		default:
+			ps = append(ps, problem{"typeRules", fmt.Sprintf("typeRules: %s: scope %q should be required, optional or forbidden", t, r.Scope)})
+		}
+		switch r.Body {
+		case "", requireRequired, requireOptional:
+		default:
+			ps = append(ps, problem{"typeRules", fmt.Sprintf("typeRules: %s: body %q should be required or optional", t, r.Body)})
+		}
+
+		for _, e := range append(append([]string{}, r.Scopes...), r.DenyScopes...) {
+			if isEmpty(e) {
+				ps = append(ps, problem{"typeRules", fmt.Sprintf("typeRules: %s: scopes contains empty string", t)})
+			} else if err := scopeEntryError(e); err != nil {
+				ps = append(ps, problem{"typeRules", fmt.Sprintf("typeRules: %s: %s: %v", t, e, err)})
+			}
+		}
+	}
+	return ps
+}
@@ -1,11 +1,16 @@
 package validator
 
 import (
+	"fmt"
 	"io/ioutil"
 	"log"
+	"math"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/JayceChant/commit-msg/state"
 )
@@ -13,10 +18,128 @@ import (
 const (
 	mergePrefix   = "Merge "
 	revertPattern = `^(Revert|revert)(:| ).+`
-	headerPattern = `^((fixup! |squash! )?(\w+)(?:\(([^\)\s]+)\))?: (.+))(?:\n|$)`
+	headerPattern = `^((fixup! |squash! )?(\w+)(?:\(([^\)\s]+)\))?(!)?: (.+))(?:\n|$)`
+	// multiScopeHeaderPattern allows spaces between the scopes, e.g. feat(api, ui),
+	// but not around them, used if MultipleScopes is true
+	multiScopeHeaderPattern = `^((fixup! |squash! )?(\w+)(?:\(([^\)\s](?:[^\)\n]*[^\)\s])?)\))?(!)?: (.+))(?:\n|$)`
 )
 
-// Validate ...
+var (
+	headerRegexp           = regexp.MustCompile(headerPattern)
+	multiScopeHeaderRegexp = regexp.MustCompile(multiScopeHeaderPattern)
+)
+
+// headerRegexp returns the regular expression of the header by MultipleScopes
+func (cfg *Config) headerRegexp() *regexp.Regexp {
+	if cfg.MultipleScopes {
+		return multiScopeHeaderRegexp
+	}
+	return headerRegexp
+}
+
+// Violation is a rule broken by a commit message
+type Violation struct {
+	// State tells which rule is broken
+	State state.State
+	// Line is the 1-based number of the offending line, 0 if not bound to a line
+	Line int
+	// Column is the 1-based column (in characters) where the violation starts, 0 if not bound to a column
+	Column int
+	// Text is the content of the offending line
+	Text string
+	// Args are the arguments to format the hint of State
+	Args []interface{}
+	// Severity is that of the rule in the config, error or warning
+	Severity Severity
+}
+
+// Result is the outcome of checking a commit message
+type Result struct {
+	// State is the most severe state of the errors in Violations,
+	// or a normal state if the message meets the rule, maybe with warnings
+	State state.State
+	// Violations are all the rules broken, both errors and warnings, in the order of lines
+	Violations []Violation
+	// Header is the parsed header, nil if the header is not in the format of the rule
+	Header *Header
+	// Trailers are the footers of the message, in the order of lines
+	Trailers []Trailer
+	// Breaking reports whether the commit introduces a breaking change,
+	// marked by "!" in the header or told by a BREAKING CHANGE footer
+	Breaking bool
+}
+
+// Header is the parsed header of a commit message, <type>(<scope>)!: <subject>
+type Header struct {
+	Type    string
+	Scope   string
+	Subject string
+	// Breaking reports whether the header is marked by "!" before the colon
+	Breaking bool
+	// FixupOrSquash reports whether the header starts with "fixup! " or "squash! "
+	FixupOrSquash bool
+}
+
+// parseHeader parses the header line, returns nil if it is not in the format of the rule
+func parseHeader(header string, config *Config) *Header {
+	groups := config.headerRegexp().FindStringSubmatch(header)
+	if groups == nil {
+		return nil
+	}
+	return &Header{
+		Type:          groups[3],
+		Scope:         groups[4],
+		Subject:       groups[6],
+		Breaking:      groups[5] != "",
+		FixupOrSquash: groups[2] != "",
+	}
+}
+
+// OK reports whether the message meets the rule
+func (r Result) OK() bool {
+	return r.State.IsNormal()
+}
+
+func violation(s state.State, line, column int, text string, args ...interface{}) Violation {
+	return Violation{State: s, Line: line, Column: column, Text: text, Args: args, Severity: SeverityError}
+}
+
+// mostSevere returns the most severe state of the errors in vs, or Validated if there are none
+func mostSevere(vs []Violation) state.State {
+	worst := state.Validated
+	for _, v := range vs {
+		if v.Severity != SeverityWarning && v.State.MoreSevere(worst) {
+			worst = v.State
+		}
+	}
+	return worst
+}
+
+// Check validates msg against cfg and returns the result, without logging or exiting.
+// All the rules are checked, rather than stopping at the first violation.
+// An error is returned if cfg is invalid, see Config.Validate.
+func Check(msg string, cfg Config) (Result, error) {
+	if err := cfg.Validate(); err != nil {
+		return Result{State: state.ConfigError}, err
+	}
+
+	if isMergeCommit(msg) {
+		return Result{State: state.Merge}, nil
+	}
+
+	vs := applySeverity(validateMsg(msg, &cfg, cfg.typeSet()), &cfg)
+	res := Result{State: mostSevere(vs), Violations: vs}
+	sections := strings.SplitN(msg, "\n", 2)
+	res.Header = parseHeader(sections[0], &cfg)
+	if len(sections) == 2 {
+		res.Trailers, _ = parseTrailers(sections[1])
+	}
+	res.Breaking = (res.Header != nil && res.Header.Breaking) || breakingTrailer(res.Trailers) != nil
+	return res, nil
+}
+
+// Validate checks the message in file against the global config,
+// logs the result and exits with the state as exit code.
 func Validate(file string) {
 	defer func() {
 		err := recover()
@@ -32,101 +155,175 @@ func Validate(file string) {
 		}
 	}()
 
-	validateMsg(getMsg(file), globalConfig)
+	if configErr != nil {
+		state.ConfigError.LogAndExit(configErr)
+	}
+
+	cfg := globalConfig
+	if isHookMessage(file) {
+		cfg = cfg.ForStagedFiles()
+	}
+	res, err := Check(getMsg(file), *cfg)
+	if err != nil {
+		state.ConfigError.LogAndExit(err)
+	}
+
+	for _, v := range res.Violations {
+		if v.Severity == SeverityWarning {
+			log.Println(v.State.Warning(v.Args...))
+		} else {
+			v.State.Log(v.Args...)
+		}
+	}
+
+	if res.OK() {
+		res.State.LogAndExit()
+	}
+	res.State.Exit()
 }
 
-func getMsg(path string) string {
-	if path == "" {
-		state.ArgumentMissing.LogAndExit()
+// Stdin is the path standing for the standard input
+const Stdin = "-"
+
+// ReadMessage reads the commit message in the file of path, or from stdin if path is "-".
+// If it fails, the state tells the kind of the failure, FileMissing or ReadError.
+func ReadMessage(path string) (string, state.State, error) {
+	if path == Stdin {
+		buf, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return "", state.ReadError, err
+		}
+		return string(buf), state.Validated, nil
 	}
 
 	f, err := os.Stat(path)
 	if err != nil && !os.IsExist(err) {
-		log.Println(err)
-		state.FileMissing.LogAndExit(path)
+		return "", state.FileMissing, err
 	}
 
 	if f.IsDir() {
-		log.Println(path, "is not a file.")
-		state.FileMissing.LogAndExit(path)
+		return "", state.FileMissing, fmt.Errorf("%s is not a file", path)
 	}
 
 	buf, err := ioutil.ReadFile(path)
 	if err != nil {
-		log.Println(err)
-		state.ReadError.LogAndExit(path)
+		return "", state.ReadError, err
 	}
 
-	return string(buf)
+	return string(buf), state.Validated, nil
 }
 
-func validateMsg(msg string, config *validateConfig) {
-	if isEmpty(msg) {
-		state.EmptyMessage.LogAndExit()
+func getMsg(path string) string {
+	if path == "" {
+		state.ArgumentMissing.LogAndExit()
+	}
+
+	msg, s, err := ReadMessage(path)
+	if err != nil {
+		log.Println(err)
+		s.LogAndExit(path)
 	}
+	return msg
+}
 
-	isMergeCommit(msg)
+func validateMsg(msg string, config *Config, types map[string]dummy) []Violation {
+	if isEmpty(msg) {
+		return []Violation{violation(state.EmptyMessage, 0, 0, "")}
+	}
 
 	sections := strings.SplitN(msg, "\n", 2)
+	if h := parseHeader(sections[0], config); h != nil {
+		// scope and body may be required by the rule of the type
+		config = config.forType(h.Type)
+	}
 
-	validateHeader(sections[0], config)
+	vs := validateHeader(sections[0], config, types)
 
+	var trailers []Trailer
+	body := ""
 	if len(sections) == 2 {
-		validateBody(sections[1], config)
+		body = sections[1]
+		vs = append(vs, validateBody(body, config)...)
+
+		var malformed []Violation
+		trailers, malformed = parseTrailers(body)
+		vs = append(vs, malformed...)
 	} else if config.BodyRequired {
-		state.BodyMissing.LogAndExit()
+		vs = append(vs, violation(state.BodyMissing, 0, 0, ""))
 	}
 
-	state.Validated.LogAndExit()
+	vs = append(vs, validateTrailers(trailers, config)...)
+	vs = append(vs, validateIssueRef(sections[0], body, trailers, config)...)
+	vs = append(vs, validateBranchTicket(msg, config)...)
+	if h := parseHeader(sections[0], config); h != nil {
+		vs = append(vs, validateBreaking(h, trailers, config)...)
+	}
+
+	// in the order of lines, those not bound to a line at last
+	sort.SliceStable(vs, func(i, j int) bool {
+		return lineOrder(vs[i]) < lineOrder(vs[j])
+	})
+	return vs
+}
+
+func lineOrder(v Violation) int {
+	if v.Line == 0 {
+		return math.MaxInt32
+	}
+	return v.Line
 }
 
 func isEmpty(str string) bool {
 	return strings.TrimSpace(str) == ""
 }
 
-func isMergeCommit(msg string) {
-	if strings.HasPrefix(msg, mergePrefix) {
-		// merge commit is auto generated by git or other tool,
-		// cannot be modified in most cases.
-		// just skip the rest validation.
-		state.Merge.LogAndExit()
-	}
+func isMergeCommit(msg string) bool {
+	// merge commit is auto generated by git or other tool,
+	// cannot be modified in most cases.
+	// just skip the rest validation.
+	return strings.HasPrefix(msg, mergePrefix)
 }
 
-func validateHeader(header string, config *validateConfig) {
+func validateHeader(header string, config *Config, types map[string]dummy) []Violation {
 	if isEmpty(header) {
-		state.EmptyHeader.LogAndExit()
+		return []Violation{violation(state.EmptyHeader, 1, 0, header)}
 	}
 
 	if isRevertHeader(header) {
 		// skip revert header checking
-		return
+		return nil
 		// but later body check is still required
 	}
 
-	re := regexp.MustCompile(headerPattern)
-	groups := re.FindStringSubmatch(header)
+	var vs []Violation
 
-	if groups == nil || isEmpty(groups[5]) {
-		state.BadHeaderFormat.LogAndExit(header)
-	}
+	groups := config.headerRegexp().FindStringSubmatchIndex(header)
 
-	typ := groups[3]
-	validateType(typ)
+	isFixupOrSquash := false
+	if groups == nil || isEmpty(header[groups[12]:groups[13]]) {
+		vs = append(vs, violation(state.BadHeaderFormat, 1, 1, header, header))
+	} else {
+		isFixupOrSquash = (groups[4] != groups[5])
 
-	isFixupOrSquash := (groups[2] != "")
+		if v := validateType(header[groups[6]:groups[7]], types); v != nil {
+			v.Line, v.Column, v.Text = 1, column(header, groups[6]), header
+			vs = append(vs, *v)
+		}
 
-	validateScope(groups[4], config)
+		scopeStart, scopeEnd := groups[7], groups[7]
+		if groups[8] >= 0 {
+			scopeStart, scopeEnd = groups[8], groups[9]
+		}
+		vs = append(vs, validateScope(header, scopeStart, scopeEnd, config)...)
+		vs = append(vs, validateTypeScope(header, header[groups[6]:groups[7]], scopeStart, scopeEnd, config)...)
 
-	// TODO: 根据规则对subject检查
-	// subject := groups[5]
+		vs = append(vs, validateSubject(header, groups[12], config)...)
+	}
 
-	length := len(header)
-	if config.LineLimit > 0 &&
-		length > config.LineLimit &&
-		!isFixupOrSquash {
-		state.LineOverLong.LogAndExit(length, config.LineLimit, header)
+	if v := validateLength(header, 1, config); v != nil && !isFixupOrSquash {
+		vs = append(vs, *v)
 	}
+	return vs
 }
 
 func isRevertHeader(header string) bool {
@@ -134,53 +331,181 @@ func isRevertHeader(header string) bool {
 	return m
 }
 
-func validateType(typ string) {
-	for t := range TypeSet {
-		if typ == t {
-			return
+func validateType(typ string, types map[string]dummy) *Violation {
+	if _, ok := types[typ]; ok {
+		return nil
+	}
+	v := violation(state.WrongType, 0, 0, "", typ, typesStr(types))
+	return &v
+}
+
+// subject cases
+const (
+	caseAny      = "any"
+	caseLower    = "lower"
+	caseUpper    = "upper"
+	caseSentence = "sentence"
+)
+
+// validateSubject checks the subject starting at offset start of header
+func validateSubject(header string, start int, config *Config) []Violation {
+	var vs []Violation
+	subject := header[start:]
+	at := func(s state.State, offset int, args ...interface{}) {
+		vs = append(vs, violation(s, 1, column(header, start+offset), header, args...))
+	}
+
+	trimmed := strings.TrimLeftFunc(subject, unicode.IsSpace)
+	if len(trimmed) < len(subject) && config.DenySubjectLeadingSpace {
+		at(state.SubjectLeadingSpace, 0)
+	}
+
+	if offset := wrongCase(trimmed, config.SubjectCase); offset >= 0 {
+		at(state.WrongSubjectCase, len(subject)-len(trimmed)+offset, config.SubjectCase, header)
+	}
+
+	for _, p := range config.DenySubjectEnd {
+		if p != "" && strings.HasSuffix(subject, p) {
+			at(state.SubjectEndPunctuation, len(subject)-len(p), p, header)
+			break
+		}
+	}
+
+	if config.SubjectImperative {
+		if word, offset := firstWord(subject); word != "" {
+			if v := imperative(word); v != "" {
+				at(state.NonImperativeSubject, offset, word, v, header)
+			}
+		}
+	}
+
+	length := utf8.RuneCountInString(subject)
+	if config.SubjectMinLength > 0 && length < config.SubjectMinLength {
+		at(state.SubjectTooShort, 0, length, config.SubjectMinLength, header)
+	}
+	if config.SubjectMaxLength > 0 && length > config.SubjectMaxLength {
+		at(state.SubjectTooLong, offsetOfRune(subject, config.SubjectMaxLength), length, config.SubjectMaxLength, header)
+	}
+	return vs
+}
+
+// wrongCase returns the byte offset of the first letter breaking the case in subject,
+// or -1 if it meets the case. lower and upper apply to all the letters,
+// while sentence only requires the first letter in upper case.
+func wrongCase(subject, c string) int {
+	for i, r := range subject {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+
+		switch c {
+		case caseLower:
+			if unicode.IsUpper(r) {
+				return i
+			}
+		case caseUpper:
+			if unicode.IsLower(r) {
+				return i
+			}
+		case caseSentence:
+			if unicode.IsLower(r) {
+				return i
+			}
+			return -1
+		default:
+			return -1
 		}
 	}
-	state.WrongType.LogAndExit(typ, TypesStr)
+	return -1
 }
 
-func validateScope(scope string, config *validateConfig) {
-	if isEmpty(scope) {
-		if config.ScopeRequired {
-			state.ScopeMissing.LogAndExit()
+// offsetOfRune returns the byte offset of the n-th (0-based) character in s
+func offsetOfRune(s string, n int) int {
+	for i := range s {
+		if n == 0 {
+			return i
 		}
-		return
+		n--
 	}
+	return len(s)
+}
 
-	if len(config.Scopes) == 0 {
-		return
+// breaking footer modes
+const (
+	breakingAny       = "any"
+	breakingRequired  = "required"
+	breakingForbidden = "forbidden"
+)
+
+// breakingTrailer returns the first trailer telling a breaking change, nil if there is none
+func breakingTrailer(trailers []Trailer) *Trailer {
+	for i := range trailers {
+		if trailers[i].isBreaking() {
+			return &trailers[i]
+		}
 	}
+	return nil
+}
 
-	for _, s := range config.Scopes {
-		if scope == s {
-			return
+// validateBreaking checks the BREAKING CHANGE footer against the "!" marker
+// of the header. If required, a marked header requires the footer and vice versa;
+// if forbidden, breaking changes are told by the marker only.
+func validateBreaking(h *Header, trailers []Trailer, config *Config) []Violation {
+	t := breakingTrailer(trailers)
+	switch config.BreakingFooter {
+	case breakingRequired:
+		if h.Breaking && t == nil {
+			return []Violation{violation(state.BreakingFooterMissing, 0, 0, "")}
+		}
+		if !h.Breaking && t != nil {
+			return []Violation{violation(state.BreakingMarkerMissing, 1, 0, "")}
+		}
+	case breakingForbidden:
+		if t != nil {
+			text := t.Token + t.Separator + firstLine(t.Value)
+			return []Violation{violation(state.BreakingFooterForbidden, t.Line, 1, text, text)}
 		}
 	}
-	state.WrongScope.LogAndExit(scope, strings.Join(config.Scopes, ", "))
+	return nil
 }
 
-func validateBody(body string, config *validateConfig) {
+// validateBody checks the rest of the message after the header,
+// line numbers reported are those in the whole message.
+func validateBody(body string, config *Config) []Violation {
 	if isEmpty(body) {
 		if config.BodyRequired {
-			state.BodyMissing.LogAndExit()
-		} else {
-			state.Validated.LogAndExit()
+			return []Violation{violation(state.BodyMissing, 0, 0, "")}
 		}
+		return nil
 	}
 
-	if !isEmpty(strings.SplitN(body, "\n", 2)[0]) {
-		state.NoBlankLineBeforeBody.LogAndExit()
+	var vs []Violation
+
+	lines := strings.Split(body, "\n")
+	if !isEmpty(lines[0]) {
+		vs = append(vs, violation(state.NoBlankLineBeforeBody, 2, 1, lines[0]))
 	}
 
-	for _, line := range strings.Split(body, "\n") {
-		length := len(line)
-		if config.LineLimit > 0 &&
-			length > config.LineLimit {
-			state.LineOverLong.LogAndExit(length, config.LineLimit, line)
+	for i, line := range lines {
+		if v := validateLength(line, i+2, config); v != nil {
+			vs = append(vs, *v)
 		}
 	}
+	return vs
+}
+
+// validateLength checks the length of line in bytes against LineLimit,
+// the column reported is the first character beyond the limit.
+func validateLength(line string, lineNum int, config *Config) *Violation {
+	length := len(line)
+	if config.LineLimit <= 0 || length <= config.LineLimit {
+		return nil
+	}
+	v := violation(state.LineOverLong, lineNum, column(line, config.LineLimit), line, length, config.LineLimit, line)
+	return &v
+}
+
+// column converts byte offset in line to 1-based character column
+func column(line string, offset int) int {
+	return utf8.RuneCountInString(line[:offset]) + 1
 }
@@ -3,6 +3,7 @@ package validator
 import (
 	"os"
 	"os/exec"
+	"reflect"
 	"runtime"
 	"strings"
 	"testing"
@@ -11,10 +12,13 @@ import (
 )
 
 var (
-	zeroCfg         = &validateConfig{}
-	defaultCfg      = &validateConfig{Lang: "en", BodyRequired: true, LineLimit: 80}
-	scopeRequired   = &validateConfig{ScopeRequired: true}
-	scopesSpecified = &validateConfig{Scopes: []string{"model", "view", "controller"}}
+	zeroCfg         = &Config{}
+	defaultCfg      = &Config{Lang: "en", BodyRequired: true, LineLimit: 80}
+	scopeRequired   = &Config{ScopeRequired: true}
+	scopesSpecified = &Config{Scopes: []string{"model", "view", "controller"}}
+	scopePatterns   = &Config{Scopes: []string{"pkg/*", `/svc-\w+/`}}
+	multipleScopes  = &Config{Scopes: []string{"model", "view", "pkg/*"}, MultipleScopes: true}
+	defaultTypes    = defaultCfg.typeSet()
 )
 
 func assertExitCode(t *testing.T, f func(), name string, expected int) {
@@ -68,6 +72,41 @@ func TestGetMsg(t *testing.T) {
 	}, "Normal", 0)
 }
 
+func TestReadMessage(t *testing.T) {
+	var cases = []struct {
+		path string
+		want state.State
+	}{
+		{"testcase/normal_sample.txt", state.Validated},
+		{"file_not_existed.txt", state.FileMissing},
+		{"testcase", state.FileMissing},
+	}
+
+	for _, c := range cases {
+		msg, got, err := ReadMessage(c.path)
+		if got != c.want {
+			t.Errorf("ReadMessage(%q) state got %v, expected %v", c.path, got, c.want)
+		}
+		if (err == nil) != (c.want == state.Validated) || (err == nil) == (msg == "") {
+			t.Errorf("ReadMessage(%q) got (%q, %v)", c.path, msg, err)
+		}
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdin := os.Stdin
+	defer func() { os.Stdin = stdin }()
+	os.Stdin = r
+	w.WriteString("feat: from stdin\n")
+	w.Close()
+
+	if msg, _, err := ReadMessage(Stdin); err != nil || msg != "feat: from stdin\n" {
+		t.Errorf("ReadMessage(%q) got (%q, %v)", Stdin, msg, err)
+	}
+}
+
 func TestValidateEmpty(t *testing.T) {
 	var emptyCases = []struct {
 		text string
@@ -89,41 +128,54 @@ func TestValidateEmpty(t *testing.T) {
 }
 
 func TestValidateType(t *testing.T) {
-	assertExitCode(t, func() {
-		validateType("feat")
-	}, "feat", 0)
-
-	assertExitCode(t, func() {
-		validateType("")
-	}, "no_type", int(state.WrongType))
-
-	assertExitCode(t, func() {
-		validateType("Feat")
-	}, "wrong_type", int(state.WrongType))
+	var typeCases = []struct {
+		text string
+		name string
+		want state.State
+	}{
+		{"feat", "feat", state.Validated},
+		{"", "no_type", state.WrongType},
+		{"Feat", "wrong_type", state.WrongType},
+	}
+	for _, tt := range typeCases {
+		got := state.Validated
+		if v := validateType(tt.text, defaultTypes); v != nil {
+			got = v.State
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
 }
 
 func TestValidateHeader(t *testing.T) {
 	var headerCases = []struct {
 		text   string
 		name   string
-		config *validateConfig
-		want   int
+		config *Config
+		want   state.State
 	}{
-		{"", "empty_header", defaultCfg, int(state.EmptyHeader)},
-		{"\r\r\t\n", "empty_header2", defaultCfg, int(state.EmptyHeader)},
-		{"something in wrong format", "bad_header_format", defaultCfg, int(state.BadHeaderFormat)},
-		{"test:header without space after colon", "bad_header_no_colon", defaultCfg, int(state.BadHeaderFormat)},
-		{"test：Chinese(full width) colon", "bad_header_full_width", defaultCfg, int(state.BadHeaderFormat)},
-		{"test: ", "bad_header_no_title", defaultCfg, int(state.BadHeaderFormat)},
-		{"feat: something changes", "scope_missing", scopeRequired, int(state.ScopeMissing)},
-		{"feat( ): something changes", "empty_scope", scopeRequired, int(state.BadHeaderFormat)},
-		{"feat: header that too lonnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnng", "header_too_long", defaultCfg, int(state.LineOverLong)},
-		{"feat: header that too lonnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnng", "header_length_no_limit", zeroCfg, 0},
+		{"", "empty_header", defaultCfg, state.EmptyHeader},
+		{"\r\r\t\n", "empty_header2", defaultCfg, state.EmptyHeader},
+		{"something in wrong format", "bad_header_format", defaultCfg, state.BadHeaderFormat},
+		{"test:header without space after colon", "bad_header_no_colon", defaultCfg, state.BadHeaderFormat},
+		{"test：Chinese(full width) colon", "bad_header_full_width", defaultCfg, state.BadHeaderFormat},
+		{"test: ", "bad_header_no_title", defaultCfg, state.BadHeaderFormat},
+		{"feat: something changes", "scope_missing", scopeRequired, state.ScopeMissing},
+		{"feat( ): something changes", "empty_scope", scopeRequired, state.BadHeaderFormat},
+		{"feat( model): something changes", "scope_leading_space", scopeRequired, state.BadHeaderFormat},
+		{"feat(api, ui): something changes", "scope_with_space", scopeRequired, state.BadHeaderFormat},
+		{"feat(some scope): something changes", "scope_with_space2", zeroCfg, state.BadHeaderFormat},
+		{"feat(model, view): something changes", "multiple_scopes_with_space", multipleScopes, state.Validated},
+		{"feat(api,ui): something changes", "multiple_scopes", scopeRequired, state.Validated},
+		{"fix(pkg/foo): something changes", "scope_with_slash", scopeRequired, state.Validated},
+		{"feat: header that too lonnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnng", "header_too_long", defaultCfg, state.LineOverLong},
+		{"feat: header that too lonnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnng", "header_length_no_limit", zeroCfg, state.Validated},
 	}
 	for _, tt := range headerCases {
-		assertExitCode(t, func() {
-			validateHeader(tt.text, tt.config)
-		}, tt.name, tt.want)
+		if got := mostSevere(validateHeader(tt.text, tt.config, defaultTypes)); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
 	}
 }
 
@@ -131,19 +183,19 @@ func TestValidateBody(t *testing.T) {
 	var bodyCases = []struct {
 		text   string
 		name   string
-		config *validateConfig
-		want   int
+		config *Config
+		want   state.State
 	}{
-		{"", "body_missing", defaultCfg, int(state.BodyMissing)},
-		{"body", "no_blank_line", defaultCfg, int(state.NoBlankLineBeforeBody)},
-		{"\r\na body with too looooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooong line", "body_line_over_long", defaultCfg, int(state.LineOverLong)},
-		{"\r\na body with too looooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooong line", "body_line_no_limit", zeroCfg, 0},
-		{"\r\nnormal body", "normal", defaultCfg, 0},
+		{"", "body_missing", defaultCfg, state.BodyMissing},
+		{"body", "no_blank_line", defaultCfg, state.NoBlankLineBeforeBody},
+		{"\r\na body with too looooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooong line", "body_line_over_long", defaultCfg, state.LineOverLong},
+		{"\r\na body with too looooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooong line", "body_line_no_limit", zeroCfg, state.Validated},
+		{"\r\nnormal body", "normal", defaultCfg, state.Validated},
 	}
 	for _, tt := range bodyCases {
-		assertExitCode(t, func() {
-			validateBody(tt.text, tt.config)
-		}, tt.name, tt.want)
+		if got := mostSevere(validateBody(tt.text, tt.config)); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
 	}
 }
 
@@ -154,33 +206,428 @@ func TestValidate(t *testing.T) {
 }
 
 func TestTortoiseGit(t *testing.T) {
-	assertExitCode(t, func() {
-		validateMsg("Merge remote-tracking branch 'remotes/origin/feat_xyz'", defaultCfg)
-	}, "Merge", 0)
+	if got, _ := Check("Merge remote-tracking branch 'remotes/origin/feat_xyz'", *defaultCfg); got.State != state.Merge {
+		t.Errorf("Merge: got %v, want %v", got.State, state.Merge)
+	}
 
-	assertExitCode(t, func() {
-		validateMsg(`Revert "fix: abc issue & xyz problems, some words to make it lonnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnger"
+	if got := validateMsg(`Revert "fix: abc issue & xyz problems, some words to make it lonnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnnger"
 
-This reverts commit 1234567890abcdef1234567890abcdef12345678.`, defaultCfg)
-	}, "Revert", 0)
+This reverts commit 1234567890abcdef1234567890abcdef12345678.`, defaultCfg, defaultTypes); len(got) != 0 {
+		t.Errorf("Revert: got %v, want no violation", got)
+	}
 }
 
 func TestValidateScope(t *testing.T) {
 	var scopeCases = []struct {
 		text   string
 		name   string
-		config *validateConfig
-		want   int
+		config *Config
+		want   state.State
 	}{
-		{"model", "normal", scopeRequired, 0},
-		{"", "empty_but_not_required", defaultCfg, 0},
-		{"", "empty_scope", scopeRequired, int(state.ScopeMissing)},
-		{"model", "scope_in_range", scopesSpecified, 0},
-		{"module", "wrong_scope", scopesSpecified, int(state.WrongScope)},
+		{"model", "normal", scopeRequired, state.Validated},
+		{"", "empty_but_not_required", defaultCfg, state.Validated},
+		{"", "empty_scope", scopeRequired, state.ScopeMissing},
+		{"model", "scope_in_range", scopesSpecified, state.Validated},
+		{"module", "wrong_scope", scopesSpecified, state.WrongScope},
+		{"pkg/foo", "glob", scopePatterns, state.Validated},
+		{"pkg/foo/bar", "glob_no_match", scopePatterns, state.WrongScope},
+		{"svc-billing", "regexp", scopePatterns, state.Validated},
+		{"my-svc-billing", "regexp_whole", scopePatterns, state.WrongScope},
+		{"api,ui", "not_multiple", scopesSpecified, state.WrongScope},
+		{"model,view", "multiple", multipleScopes, state.Validated},
+		{"model, pkg/foo", "multiple_patterns", multipleScopes, state.Validated},
+		{"model,", "multiple_empty", multipleScopes, state.BadHeaderFormat},
+		{"api,", "multiple_empty_any", &Config{MultipleScopes: true}, state.BadHeaderFormat},
+		{"api, ,ui", "multiple_blank_any", &Config{MultipleScopes: true}, state.BadHeaderFormat},
+		{"model|view", "delimiter", &Config{Scopes: []string{"model", "view"}, MultipleScopes: true, ScopeDelimiter: "|"}, state.Validated},
 	}
 	for _, tt := range scopeCases {
-		assertExitCode(t, func() {
-			validateScope(tt.text, tt.config)
-		}, tt.name, tt.want)
+		if got := mostSevere(validateScope(tt.text, 0, len(tt.text), tt.config)); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	header := "feat(model, module, view): something changes"
+	vs := validateHeader(header, multipleScopes, defaultTypes)
+	want := []Violation{violation(state.WrongScope, 1, 13, header, "module", "model, view, pkg/*")}
+	if !reflect.DeepEqual(vs, want) {
+		t.Errorf("%s: got %+v, want %+v", header, vs, want)
+	}
+}
+
+func TestScopePaths(t *testing.T) {
+	var pathCases = []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"services/billing/**", "services/billing/api/main.go", true},
+		{"services/billing/**", "services/billing", true},
+		{"services/billing/**", "services/billingx/main.go", false},
+		{"services/*/api/**", "services/users/api/v1/user.go", true},
+		{"**/*.md", "docs/guide/intro.md", true},
+		{"**/*.md", "README.md", true},
+		{"web/*", "web/src/app.js", false},
+	}
+	for _, tt := range pathCases {
+		if got := matchPath(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("matchPath(%q, %q) got %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+
+	cfg := &Config{ScopePaths: map[string]string{
+		"services/billing/**": "billing",
+		"services/users/**":   "users",
+		"services/**":         "services",
+		"**/*.md":             "docs",
+	}}
+	if got := cfg.PathScope("services/billing/README.md"); got != "billing" {
+		t.Errorf("PathScope got %q, want the longest pattern billing", got)
+	}
+
+	var checkCases = []struct {
+		text   string
+		files  []string
+		config *Config
+		want   state.State
+	}{
+		{"fix(billing): something changes", []string{"services/billing/main.go", "go.mod"}, cfg, state.Validated},
+		{"fix(users): something changes", []string{"services/billing/main.go"}, cfg, state.ScopeMismatch},
+		{"fix: something changes", []string{"services/billing/main.go"}, cfg, state.ScopeMismatch},
+		{"fix: something changes", []string{"go.mod"}, cfg, state.Validated},
+		{"fix(billing): something changes", nil, cfg, state.Validated},
+		{"fix(billing): something changes", []string{"services/billing/main.go", "services/users/main.go"}, cfg, state.Validated},
+		{"fix(users): something changes", []string{"services/billing/main.go", "services/users/main.go"}, cfg, state.Validated},
+		{"fix(web): something changes", []string{"services/billing/main.go", "services/users/main.go"}, cfg, state.ScopeMismatch},
+		{"fix(billing): something changes", []string{"services/billing/main.go", "services/users/main.go"}, cfg.Merge(&Config{MultipleScopes: true}), state.ScopeMismatch},
+		{"fix(billing,users): something changes", []string{"services/billing/main.go", "services/users/main.go"}, cfg.Merge(&Config{MultipleScopes: true}), state.Validated},
+	}
+	for _, tt := range checkCases {
+		res, err := Check(tt.text, *tt.config.ForFiles(tt.files))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.State != tt.want {
+			t.Errorf("%q with %v: got %v, want %v", tt.text, tt.files, res.State, tt.want)
+		}
+	}
+
+	header := "fix(users): something changes"
+	vs := validateHeader(header, cfg.Merge(&Config{MultipleScopes: true}).ForFiles([]string{"services/users/a.go", "services/billing/b.go"}), defaultTypes)
+	want := []Violation{violation(state.ScopeMismatch, 1, 5, header, "users", "billing,users")}
+	if !reflect.DeepEqual(vs, want) {
+		t.Errorf("%s: got %+v, want %+v", header, vs, want)
+	}
+
+	// a single scope is suggested without multipleScopes, which the header accepts
+	header = "fix(web): something changes"
+	vs = validateHeader(header, cfg.ForFiles([]string{"services/users/a.go", "services/billing/b.go"}), defaultTypes)
+	want = []Violation{violation(state.ScopeMismatch, 1, 5, header, "web", "billing")}
+	if !reflect.DeepEqual(vs, want) {
+		t.Errorf("%s: got %+v, want %+v", header, vs, want)
+	}
+	if res, _ := Check("fix(billing): something changes", *cfg); res.State != state.Validated {
+		t.Errorf("suggested header got %v", res.State)
+	}
+
+	for file, want := range map[string]bool{".git/COMMIT_EDITMSG": true, "msg.txt": false, Stdin: false} {
+		if got := isHookMessage(file); got != want {
+			t.Errorf("isHookMessage(%q) got %v, want %v", file, got, want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	var checkCases = []struct {
+		text string
+		name string
+		want []Violation
+	}{
+		{"feat(model): some changes\n\nbody", "normal", nil},
+		{"  \n", "empty_message", []Violation{{State: state.EmptyMessage}}},
+		{"feat(model): some changes", "body_missing", []Violation{{State: state.BodyMissing}}},
+		{"Feat: some changes\n\nbody", "wrong_type", []Violation{{State: state.WrongType, Line: 1, Column: 1}}},
+		{"fixup! feat(mdl): some changes\n\nbody", "fixup", nil},
+		{"feat: some changes\n\nbody\nlooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooong", "line_over_long", []Violation{{State: state.LineOverLong, Line: 4, Column: 81}}},
+		{"Feat: some changes\nbody\nlooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooooong", "all_violations", []Violation{
+			{State: state.WrongType, Line: 1, Column: 1},
+			{State: state.NoBlankLineBeforeBody, Line: 2, Column: 1},
+			{State: state.LineOverLong, Line: 3, Column: 81},
+		}},
+	}
+	for _, tt := range checkCases {
+		got, err := Check(tt.text, *defaultCfg)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if got.State != mostSevere(tt.want) {
+			t.Errorf("%s: got state %v, want %v", tt.name, got.State, mostSevere(tt.want))
+		}
+		if len(got.Violations) != len(tt.want) {
+			t.Errorf("%s: got %d violations, want %d", tt.name, len(got.Violations), len(tt.want))
+			continue
+		}
+		for i, v := range got.Violations {
+			w := tt.want[i]
+			if v.State != w.State || v.Line != w.Line || v.Column != w.Column {
+				t.Errorf("%s: violation %d got %v:%d:%d, want %v:%d:%d", tt.name, i, v.State, v.Line, v.Column, w.State, w.Line, w.Column)
+			}
+		}
+	}
+}
+
+func TestValidateSubject(t *testing.T) {
+	var subjectCases = []struct {
+		header string
+		config *Config
+		want   []Violation
+	}{
+		{"feat: add x", &Config{SubjectCase: caseLower}, nil},
+		{"feat:  add x", &Config{DenySubjectLeadingSpace: true}, []Violation{{State: state.SubjectLeadingSpace, Column: 7}}},
+		{"feat:  add x", zeroCfg, nil},
+		{"feat: add X", &Config{SubjectCase: caseLower}, []Violation{{State: state.WrongSubjectCase, Column: 11}}},
+		{"feat: ADD X", &Config{SubjectCase: caseUpper}, nil},
+		{"feat: add x", &Config{SubjectCase: caseSentence}, []Violation{{State: state.WrongSubjectCase, Column: 7}}},
+		{"feat: 2 Add x", &Config{SubjectCase: caseSentence}, nil},
+		{"feat: 添加 x", &Config{SubjectCase: caseSentence}, nil},
+		{"feat: add x.", &Config{DenySubjectEnd: []string{".", "。"}}, []Violation{{State: state.SubjectEndPunctuation, Column: 12}}},
+		{"feat: 添加。", &Config{DenySubjectEnd: []string{".", "。"}}, []Violation{{State: state.SubjectEndPunctuation, Column: 9}}},
+		{"feat: add", &Config{SubjectMinLength: 5}, []Violation{{State: state.SubjectTooShort, Column: 7}}},
+		{"feat: 添加一些", &Config{SubjectMaxLength: 3}, []Violation{{State: state.SubjectTooLong, Column: 10}}},
+		{"feat: 添加", &Config{SubjectMaxLength: 3}, nil},
+		{"feat: Added x", &Config{SubjectImperative: true}, []Violation{{State: state.NonImperativeSubject, Column: 7}}},
+		{"feat: add x", &Config{SubjectImperative: true}, nil},
+		{"feat: Added x", zeroCfg, nil},
+	}
+	for _, tt := range subjectCases {
+		got := validateSubject(tt.header, len("feat: "), tt.config)
+		if len(got) != len(tt.want) {
+			t.Errorf("%q: got %v, want %v", tt.header, got, tt.want)
+			continue
+		}
+		for i, v := range got {
+			if v.State != tt.want[i].State || v.Line != 1 || v.Column != tt.want[i].Column {
+				t.Errorf("%q: got %v:%d:%d, want %v:1:%d", tt.header, v.State, v.Line, v.Column, tt.want[i].State, tt.want[i].Column)
+			}
+		}
+	}
+}
+
+func TestImperative(t *testing.T) {
+	var imperativeCases = []struct {
+		word string
+		want string
+	}{
+		{"add", ""},
+		{"Added", "add"},
+		{"adds", "add"},
+		{"adding", "add"},
+		{"updated", "update"},
+		{"updates", "update"},
+		{"using", "use"},
+		{"stopped", "stop"},
+		{"logging", "log"},
+		{"fixes", "fix"},
+		{"copied", "copy"},
+		{"applies", "apply"},
+		{"wrote", "write"},
+		{"built", "build"},
+		{"process", ""},
+		{"speed", ""},
+		{"docs", ""},
+		{"initial", ""},
+	}
+	for _, tt := range imperativeCases {
+		if got := imperative(tt.word); got != tt.want {
+			t.Errorf("imperative(%q) got %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestBreaking(t *testing.T) {
+	var breakingCases = []struct {
+		text     string
+		mode     string
+		want     state.State
+		breaking bool
+	}{
+		{"feat(api)!: drop v1", "", state.Validated, true},
+		{"feat!: drop v1\n\nBREAKING CHANGE: v1 is gone", breakingRequired, state.Validated, true},
+		{"feat!: drop v1", breakingRequired, state.BreakingFooterMissing, true},
+		{"feat: drop v1\n\nBREAKING CHANGE: v1 is gone", breakingRequired, state.BreakingMarkerMissing, true},
+		{"feat: drop v1\n\nBREAKING-CHANGE: v1 is gone", "", state.Validated, true},
+		{"feat!: drop v1\n\nBREAKING CHANGE: v1 is gone", breakingForbidden, state.BreakingFooterForbidden, true},
+		{"feat!: drop v1", breakingForbidden, state.Validated, true},
+		{"feat: add v2", breakingRequired, state.Validated, false},
+	}
+	for _, tt := range breakingCases {
+		res, err := Check(tt.text, Config{BreakingFooter: tt.mode})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.State != tt.want || res.Breaking != tt.breaking {
+			t.Errorf("%q with %q: got (%v, %v), want (%v, %v)", tt.text, tt.mode, res.State, res.Breaking, tt.want, tt.breaking)
+		}
+	}
+
+	h := parseHeader("fixup! feat(api)!: drop v1", zeroCfg)
+	want := &Header{Type: "feat", Scope: "api", Subject: "drop v1", Breaking: true, FixupOrSquash: true}
+	if !reflect.DeepEqual(h, want) {
+		t.Errorf("parseHeader got %+v, want %+v", h, want)
+	}
+}
+
+func TestCheckSeverity(t *testing.T) {
+	cfg := Config{
+		BodyRequired:  true,
+		LineLimit:     10,
+		ScopeRequired: true,
+		Severity:      map[string]Severity{"LineOverLong": SeverityWarning, "ScopeMissing": SeverityOff},
+	}
+
+	var severityCases = []struct {
+		text string
+		want state.State
+		sevs []Severity
+	}{
+		{"feat: long subject\n\nbody", state.Validated, []Severity{SeverityWarning}},
+		{"feat: long subject", state.BodyMissing, []Severity{SeverityWarning, SeverityError}},
+		{"feat: ok\n\nbody", state.Validated, nil},
+	}
+	for _, tt := range severityCases {
+		got, err := Check(tt.text, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.State != tt.want {
+			t.Errorf("%q: got state %v, want %v", tt.text, got.State, tt.want)
+		}
+		var sevs []Severity
+		for _, v := range got.Violations {
+			sevs = append(sevs, v.Severity)
+		}
+		if !reflect.DeepEqual(sevs, tt.sevs) {
+			t.Errorf("%q: got severities %v, want %v", tt.text, sevs, tt.sevs)
+		}
+	}
+
+	cfg.Severity = map[string]Severity{"LineOverLong": SeverityOff, "LineOverLong.body": SeverityWarning, "LineOverLong.header": SeverityError}
+	got, err := Check("feat(x): long subject\n\nlong body line", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Violation{
+		{State: state.LineOverLong, Line: 1, Severity: SeverityError},
+		{State: state.LineOverLong, Line: 3, Severity: SeverityWarning},
+	}
+	if len(got.Violations) != len(want) || got.State != state.LineOverLong {
+		t.Fatalf("severity by part got %v, %+v", got.State, got.Violations)
+	}
+	for i, v := range got.Violations {
+		if v.State != want[i].State || v.Line != want[i].Line || v.Severity != want[i].Severity {
+			t.Errorf("severity by part: violation %d got %v:%d %s, want %v:%d %s", i, v.State, v.Line, v.Severity, want[i].State, want[i].Line, want[i].Severity)
+		}
+	}
+}
+
+func TestParseTrailers(t *testing.T) {
+	body := "\nbody\n\nNote: not a trailer\n\nRefs: #12\nReviewed-by: Alice\n  and Bob\nCloses #34\nBREAKING CHANGE: drop v1\nmigrate with v2\n"
+	trailers, vs := parseTrailers(body)
+	want := []Trailer{
+		{Token: "Refs", Separator: ": ", Value: "#12", Line: 7},
+		{Token: "Reviewed-by", Separator: ": ", Value: "Alice\n  and Bob", Line: 8},
+		{Token: "Closes", Separator: " #", Value: "34", Line: 10},
+		{Token: "BREAKING CHANGE", Separator: ": ", Value: "drop v1\nmigrate with v2", Line: 11},
+	}
+	if !reflect.DeepEqual(trailers, want) || len(vs) != 0 {
+		t.Errorf("parseTrailers got %+v, %v, want %+v", trailers, vs, want)
+	}
+
+	var proseCases = []string{
+		"\nsee https://example.com\n",
+		"\nstd::move is now used.",
+		"\nNote: the cache is dropped,\nwhich is rebuilt on start.\n\nMore words.",
+		"\nbody\n\nRefs:#12\nsee the issue",
+	}
+	for _, body := range proseCases {
+		if trailers, vs := parseTrailers(body); trailers != nil || vs != nil {
+			t.Errorf("parseTrailers of body %q without footer got %+v, %v", body, trailers, vs)
+		}
+	}
+
+	trailers, _ = parseTrailers("\nNote: the cache is dropped,\nwhich is rebuilt on start.\n\nRefs: #12")
+	if len(trailers) != 1 || trailers[0].Token != "Refs" || trailers[0].Line != 6 {
+		t.Errorf("parseTrailers of Note: in the body got %+v", trailers)
+	}
+
+	_, vs = parseTrailers("\nbody\n\nRefs:#12\nSigned-off-by: Alice")
+	if len(vs) != 1 || vs[0].State != state.MalformedTrailer || vs[0].Line != 5 {
+		t.Errorf("parseTrailers of malformed trailer got %v", vs)
+	}
+
+	trailers, vs = parseTrailers("\nbody\n\nRefs:#12")
+	if trailers != nil || len(vs) != 1 || vs[0].State != state.MalformedTrailer || vs[0].Line != 5 {
+		t.Errorf("parseTrailers of single malformed trailer got %+v, %v", trailers, vs)
+	}
+}
+
+func TestValidateTrailers(t *testing.T) {
+	cfg := &Config{
+		Trailers:         []string{"Refs", "Signed-off-by"},
+		RequiredTrailers: []string{"Signed-off-by"},
+		TrailerPatterns:  map[string]string{"refs": `^#\d+$`},
+	}
+	var trailerCases = []struct {
+		text string
+		want state.State
+	}{
+		{"feat: x\n\nRefs: #12\nSigned-off-by: Alice", state.Validated},
+		{"feat: x\n\nsigned-off-by: Alice\nBREAKING CHANGE: drop v1", state.Validated},
+		{"feat: x\n\nRefs: #12", state.TrailerMissing},
+		{"feat: x\n\nRefs: 12\nSigned-off-by: Alice", state.BadTrailerValue},
+		{"feat: x\n\nFixes: #12\nSigned-off-by: Alice", state.WrongTrailer},
+		{"feat: x\n\nRefs: #12\nSigned-off-by:Alice", state.MalformedTrailer},
+		{"feat: x\n\nNote: ordinary prose.\n\nRefs: #12\nSigned-off-by: Alice", state.Validated},
+		{"fix: x\n\nstd::move is now used.", state.TrailerMissing},
+		{"fix: x\n\nRefs:#12", state.MalformedTrailer},
+	}
+	for _, tt := range trailerCases {
+		res, err := Check(tt.text, *cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.State != tt.want {
+			t.Errorf("%q: got %v, want %v", tt.text, res.State, tt.want)
+		}
+	}
+}
+
+func TestValidateIssueRef(t *testing.T) {
+	var issueCases = []struct {
+		text   string
+		config *Config
+		want   state.State
+	}{
+		{"feat: add x", &Config{}, state.Validated},
+		{"feat: add x", &Config{IssuePattern: `[A-Z]+-\d+`}, state.IssueRefMissing},
+		{"feat: add x PROJ-12", &Config{IssuePattern: `[A-Z]+-\d+`}, state.Validated},
+		{"feat: add x\n\nfor PROJ-12", &Config{IssuePattern: `[A-Z]+-\d+`}, state.Validated},
+		{"feat: add x\n\nfor PROJ-12", &Config{IssuePattern: `[A-Z]+-\d+`, IssueLocations: []string{"subject", "trailers"}}, state.IssueRefMissing},
+		{"feat: add x\n\nRefs: #12", &Config{IssuePattern: `#\d+`, IssueLocations: []string{"trailers"}}, state.Validated},
+		{"feat: add x\n\nCloses: #12", &Config{IssuePattern: `#\d+`, IssueTrailers: []string{"Refs"}}, state.IssueRefMissing},
+		{"feat: add x\n\nbody\n\nRefs: #12", &Config{IssuePattern: `#\d+`, IssueLocations: []string{"body"}}, state.IssueRefMissing},
+		{"feat: add x OPS-1", &Config{IssuePattern: `[A-Z]+-\d+`, IssuePrefixes: []string{"PROJ"}}, state.IssueRefMissing},
+		{"feat: add x PROJECT-1", &Config{IssuePattern: `[A-Z]+-\d+`, IssuePrefixes: []string{"PROJ"}}, state.IssueRefMissing},
+		{"feat: add x PROJ-1", &Config{IssuePattern: `[A-Z]+-\d+`, IssuePrefixes: []string{"OPS", "PROJ"}}, state.Validated},
+	}
+	for _, tt := range issueCases {
+		res, err := Check(tt.text, *tt.config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.State != tt.want {
+			t.Errorf("%q: got %v, want %v", tt.text, res.State, tt.want)
+		}
 	}
 }